@@ -0,0 +1,231 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Bracket is the pair of opening and closing delimiters
+type Bracket struct {
+	Open  string
+	Close string
+}
+
+// Quote is the syntax of string literal, the brackets inside are not counted
+type Quote struct {
+	Open string
+	// Close defaults to Open
+	Close string
+	// Escape escapes the next byte, zero means no escape
+	Escape byte
+}
+
+// BalancedOptions configure the syntax inside the balanced brackets.
+// The nil field means the default, the empty slice means none.
+type BalancedOptions struct {
+	// Brackets default to (), [] and {}
+	Brackets []Bracket
+	// Quotes default to "" and '' with backslash escape
+	Quotes []Quote
+	// Comments default to none
+	Comments []Comment
+}
+
+var defaultBalancedOptions = &BalancedOptions{
+	Brackets: []Bracket{{"(", ")"}, {"[", "]"}, {"{", "}"}},
+	Quotes:   []Quote{{Open: `"`, Escape: '\\'}, {Open: `'`, Escape: '\\'}},
+}
+
+// UnbalancedError is reported if the opening bracket, quote or comment is not closed properly
+type UnbalancedError struct {
+	// Position is where the unexpected found
+	Position
+	// Found is the unexpected, like `"]"` or "EOF"
+	Found   string
+	Open    string
+	Close   string
+	Opening Position
+}
+
+func (err *UnbalancedError) Error() string {
+	return fmt.Sprintf("%v: unexpected %s, expecting %q to close %q at %v",
+		err.Position, err.Found, err.Close, err.Open, err.Opening)
+}
+
+type opening struct {
+	open   string
+	close  string
+	offset int
+}
+
+// SkipBalanced skip from the opening bracket to the matching closing one, returns how many bytes skipped.
+// The nested brackets, quotes and comments are honored, nil options means all default.
+// If the brackets are not balanced, nothing is skipped and *UnbalancedError is reported.
+func (src *Source) SkipBalanced(opts *BalancedOptions) int {
+	if src.Error() != nil {
+		return 0
+	}
+	opts = opts.withDefaults()
+	longest := opts.longest()
+	start := src.nextIdx
+	bracket := opts.findBracket(src.peekUpTo(longest), true)
+	if bracket == nil {
+		expected := make([]string, len(opts.Brackets))
+		for i, bracket := range opts.Brackets {
+			expected[i] = strconv.Quote(bracket.Open)
+		}
+		src.ReportExpected(expected...)
+		src.ReportError(src.ExpectedError())
+		return 0
+	}
+	stack := []opening{{bracket.Open, bracket.Close, src.Offset()}}
+	src.ReadN(len(bracket.Open))
+	for len(stack) > 0 {
+		ahead := src.peekUpTo(longest)
+		if src.Error() != nil {
+			return 0
+		}
+		top := stack[len(stack)-1]
+		if len(ahead) == 0 {
+			return src.unbalanced(start, &UnbalancedError{Position: src.Position(), Found: "EOF",
+				Open: top.open, Close: top.close, Opening: src.PositionOf(top.offset)})
+		}
+		if skipped, err := opts.skipCommentOrQuote(src, ahead); err != nil {
+			return src.unbalanced(start, err)
+		} else if skipped {
+			continue
+		}
+		if hasPrefix(ahead, top.close) {
+			src.ReadN(len(top.close))
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if bracket := opts.findBracket(ahead, true); bracket != nil {
+			stack = append(stack, opening{bracket.Open, bracket.Close, src.Offset()})
+			src.ReadN(len(bracket.Open))
+			continue
+		}
+		if bracket := opts.findBracket(ahead, false); bracket != nil {
+			return src.unbalanced(start, &UnbalancedError{Position: src.Position(), Found: strconv.Quote(bracket.Close),
+				Open: top.open, Close: top.close, Opening: src.PositionOf(top.offset)})
+		}
+		src.ReadN(1)
+	}
+	if src.Error() != nil {
+		return 0
+	}
+	return src.nextIdx - start
+}
+
+// unbalanced move the cursor back to start, then report the error
+func (src *Source) unbalanced(start int, err *UnbalancedError) int {
+	src.nextIdx = start
+	src.ReportError(err)
+	return 0
+}
+
+// skipCommentOrQuote skips the comment or quote at cursor, tells if skipped
+func (opts *BalancedOptions) skipCommentOrQuote(src *Source, ahead []byte) (bool, *UnbalancedError) {
+	if comment := findComment(opts.Comments, ahead); comment != nil {
+		return true, src.skipComment(*comment)
+	}
+	for _, quote := range opts.Quotes {
+		if hasPrefix(ahead, quote.Open) {
+			return true, src.skipQuote(quote)
+		}
+	}
+	return false, nil
+}
+
+// skipQuote skips the string literal at cursor, including the closing quote
+func (src *Source) skipQuote(quote Quote) *UnbalancedError {
+	start := src.Offset()
+	closing := quote.Close
+	if closing == "" {
+		closing = quote.Open
+	}
+	src.ReadN(len(quote.Open))
+	src.EnterLiteral()
+	defer src.LeaveLiteral()
+	for {
+		ahead := src.peekUpTo(len(closing))
+		switch {
+		case src.Error() != nil:
+			// the caller checks the error condition
+			return nil
+		case len(ahead) == 0:
+			return &UnbalancedError{Position: src.Position(), Found: "EOF",
+				Open: quote.Open, Close: closing, Opening: src.PositionOf(start)}
+		case quote.Escape != 0 && ahead[0] == quote.Escape:
+			src.ReadN(1)
+			if len(src.peekUpTo(1)) > 0 {
+				src.ReadN(1)
+			}
+		case hasPrefix(ahead, closing):
+			src.ReadN(len(closing))
+			return nil
+		default:
+			src.ReadN(1)
+		}
+	}
+}
+
+// withDefaults fill the nil fields with the default
+func (opts *BalancedOptions) withDefaults() *BalancedOptions {
+	if opts == nil {
+		return defaultBalancedOptions
+	}
+	if opts.Brackets != nil && opts.Quotes != nil {
+		return opts
+	}
+	filled := *opts
+	if filled.Brackets == nil {
+		filled.Brackets = defaultBalancedOptions.Brackets
+	}
+	if filled.Quotes == nil {
+		filled.Quotes = defaultBalancedOptions.Quotes
+	}
+	return &filled
+}
+
+func (opts *BalancedOptions) longest() int {
+	longest := 1
+	for _, bracket := range opts.Brackets {
+		longest = maxLen(longest, bracket.Open, bracket.Close)
+	}
+	for _, quote := range opts.Quotes {
+		longest = maxLen(longest, quote.Open, quote.Close)
+	}
+	for _, comment := range opts.Comments {
+		longest = maxLen(longest, comment.Start, comment.End)
+	}
+	return longest
+}
+
+func maxLen(longest int, strs ...string) int {
+	for _, str := range strs {
+		if len(str) > longest {
+			longest = len(str)
+		}
+	}
+	return longest
+}
+
+// findBracket finds the bracket opening or closing at ahead
+func (opts *BalancedOptions) findBracket(ahead []byte, open bool) *Bracket {
+	for i := range opts.Brackets {
+		bracket := &opts.Brackets[i]
+		delim := bracket.Close
+		if open {
+			delim = bracket.Open
+		}
+		if hasPrefix(ahead, delim) {
+			return bracket
+		}
+	}
+	return nil
+}
+
+func hasPrefix(ahead []byte, prefix string) bool {
+	return len(prefix) > 0 && len(ahead) >= len(prefix) && string(ahead[:len(prefix)]) == prefix
+}
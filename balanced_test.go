@@ -0,0 +1,24 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_SkipBalanced(t *testing.T) {
+	t.Run("nested", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, `{"}": [1]} x`)[0].(*parse.Source)
+		must.Equal(10, src.SkipBalanced(nil))
+		must.Equal(byte(' '), src.Peek1())
+	}))
+	t.Run("unbalanced", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "(a\n]")[0].(*parse.Source)
+		must.Equal(0, src.SkipBalanced(nil))
+		must.Equal(0, src.Offset())
+		must.Equal(`2:1: unexpected "]", expecting ")" to close "(" at 1:1`, src.Error().Error())
+	}))
+}
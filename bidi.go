@@ -0,0 +1,72 @@
+package parse
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// SpoofingError is reported when the text might look different from how it parses
+type SpoofingError struct {
+	Position
+	Message string
+}
+
+func (err *SpoofingError) Error() string {
+	return fmt.Sprintf("%v: %s", err.Position, err.Message)
+}
+
+// IsBidiControl tells if the rune is the explicit bidirectional formatting character,
+// the embeddings, overrides and isolates.
+func IsBidiControl(r rune) bool {
+	return 0x202A <= r && r <= 0x202E || 0x2066 <= r && r <= 0x2069
+}
+
+// WithBidiCheck reports *SpoofingError if bidi control is peeked outside literal.
+// The lexer should call EnterLiteral and LeaveLiteral around the string literal.
+func WithBidiCheck() SourceOption {
+	return func(src *Source) {
+		src.bidiCheck = true
+	}
+}
+
+// EnterLiteral tells the source the cursor is inside literal, bidi control is allowed
+func (src *Source) EnterLiteral() {
+	src.literals++
+}
+
+// LeaveLiteral tells the source the literal is finished
+func (src *Source) LeaveLiteral() {
+	if src.literals > 0 {
+		src.literals--
+	}
+}
+
+// checkBidi reports the bidi control at readBytes[idx:]
+func (src *Source) checkBidi(idx int) bool {
+	if !src.bidiCheck || src.literals > 0 {
+		return false
+	}
+	r, _ := utf8.DecodeRune(src.readBytes[idx:])
+	if !IsBidiControl(r) {
+		return false
+	}
+	src.ReportError(&SpoofingError{
+		Position: src.positionAt(idx),
+		Message:  fmt.Sprintf("bidi control %U outside string literal", r),
+	})
+	return true
+}
+
+// checkBidiIn reports the first bidi control in buf, the bytes at the cursor
+func (src *Source) checkBidiIn(buf []byte) bool {
+	if !src.bidiCheck || src.literals > 0 {
+		return false
+	}
+	for i, b := range buf {
+		// the bidi controls are encoded as E2 80 xx or E2 81 xx
+		if b == 0xE2 && src.checkBidi(src.nextIdx+i) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_BidiCheck(t *testing.T) {
+	t.Run("disabled by default", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a\u202Eb")[0].(*parse.Source)
+		must.Equal("a\u202Eb", string(src.ReadN(5)))
+		must.Nil(src.Error())
+	}))
+	t.Run("peek", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "x\n a\u2067b", parse.WithBidiCheck())[0].(*parse.Source)
+		src.ReadN(3)
+		read.Identifier(src, nil)
+		must.Equal("2:3: bidi control U+2067 outside string literal", src.Error().Error())
+	}))
+	t.Run("read", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "ab\u202A", parse.WithBidiCheck())[0].(*parse.Source)
+		must.Nil(src.ReadN(5))
+		must.Equal(0, src.Offset())
+		must.Equal("1:3: bidi control U+202A outside string literal", src.Error().Error())
+	}))
+	t.Run("allowed in literal", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\"a\u202Eb\"c", parse.WithBidiCheck())[0].(*parse.Source)
+		src.Expect1('"')
+		src.EnterLiteral()
+		for src.Peek1() != '"' {
+			src.Read1()
+		}
+		src.Read1()
+		src.LeaveLiteral()
+		must.Equal(byte('c'), src.Read1())
+		must.Nil(src.Error())
+	}))
+	t.Run("literal is left on rollback", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\u202E", parse.WithBidiCheck())[0].(*parse.Source)
+		src.StoreSavepoint()
+		src.EnterLiteral()
+		src.RollbackToSavepoint()
+		src.Peek1()
+		must.Equal("1:1: bidi control U+202E outside string literal", src.Error().Error())
+	}))
+	t.Run("other punctuation", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\u2014", parse.WithBidiCheck())[0].(*parse.Source)
+		must.Equal("\u2014", string(src.ReadN(3)))
+		must.Nil(src.Error())
+	}))
+}
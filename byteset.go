@@ -0,0 +1,86 @@
+package parse
+
+// ByteSet is the set of bytes as 256 bits table, for the hot loop of scanning bytes
+type ByteSet [8]uint32
+
+// NewByteSet creates the set of the bytes in chars
+func NewByteSet(chars string) *ByteSet {
+	set := &ByteSet{}
+	for i := 0; i < len(chars); i++ {
+		set.Add(chars[i])
+	}
+	return set
+}
+
+// NewByteRange creates the set of bytes from lo to hi, both inclusive
+func NewByteRange(lo, hi byte) *ByteSet {
+	return (&ByteSet{}).AddRange(lo, hi)
+}
+
+// NewByteSetFunc creates the set of bytes matching the predicate
+func NewByteSetFunc(pred func(b byte) bool) *ByteSet {
+	set := &ByteSet{}
+	for i := 0; i < 256; i++ {
+		if pred(byte(i)) {
+			set.Add(byte(i))
+		}
+	}
+	return set
+}
+
+// Add the byte into set, returns the set itself
+func (set *ByteSet) Add(b byte) *ByteSet {
+	set[b>>5] |= 1 << (b & 31)
+	return set
+}
+
+// AddRange add the bytes from lo to hi into set, returns the set itself
+func (set *ByteSet) AddRange(lo, hi byte) *ByteSet {
+	for i := int(lo); i <= int(hi); i++ {
+		set.Add(byte(i))
+	}
+	return set
+}
+
+// Union returns the new set having bytes of both
+func (set *ByteSet) Union(other *ByteSet) *ByteSet {
+	union := &ByteSet{}
+	for i := range set {
+		union[i] = set[i] | other[i]
+	}
+	return union
+}
+
+// Complement returns the new set having bytes not in the set
+func (set *ByteSet) Complement() *ByteSet {
+	complement := &ByteSet{}
+	for i := range set {
+		complement[i] = ^set[i]
+	}
+	return complement
+}
+
+// Contains tells if the byte is in set
+func (set *ByteSet) Contains(b byte) bool {
+	return set[b>>5]&(1<<(b&31)) != 0
+}
+
+// Span returns the length of leading bytes in set
+func (set *ByteSet) Span(data []byte) int {
+	for i, b := range data {
+		if set[b>>5]&(1<<(b&31)) == 0 {
+			return i
+		}
+	}
+	return len(data)
+}
+
+// Index returns the index of first byte in set, -1 if not found
+func (set *ByteSet) Index(data []byte) int {
+	for i, b := range data {
+		if set[b>>5]&(1<<(b&31)) != 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,30 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestByteSet(t *testing.T) {
+	t.Run("literals", test.Case(func(ctx context.Context) {
+		set := parse.NewByteSet(" \t\xff")
+		must.Equal(true, set.Contains(' '))
+		must.Equal(true, set.Contains(0xff))
+		must.Equal(false, set.Contains('a'))
+	}))
+	t.Run("range and union", test.Case(func(ctx context.Context) {
+		hex := parse.NewByteRange('0', '9').AddRange('a', 'f').Union(parse.NewByteRange('A', 'F'))
+		must.Equal(4, hex.Span([]byte("09aF-1")))
+		must.Equal(-1, hex.Index([]byte("xyz")))
+	}))
+	t.Run("predicate and complement", test.Case(func(ctx context.Context) {
+		upper := parse.NewByteSetFunc(func(b byte) bool { return 'A' <= b && b <= 'Z' })
+		must.Equal(2, upper.Index([]byte("abCd")))
+		must.Equal(2, upper.Complement().Span([]byte("abCd")))
+		must.Equal(false, upper.Complement().Contains('Q'))
+	}))
+}
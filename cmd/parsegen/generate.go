@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+type generator struct {
+	buf  bytes.Buffer
+	spec *spec
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// generate emit the lexer source, formatted by gofmt
+func generate(s *spec, source string) ([]byte, error) {
+	prefix, infix, err := s.dispatch()
+	if err != nil {
+		return nil, err
+	}
+	if len(prefix.slots) > 256 || len(infix.slots) > 256 {
+		return nil, fmt.Errorf("too many token slots")
+	}
+	g := &generator{spec: s}
+	g.printf("// Code generated by parsegen from %s. DO NOT EDIT.\n\n", source)
+	g.printf("package %s\n\n", s.pkg)
+	g.printf("import (\n\"io\"\n\n\"github.com/modern-go/parse\"\n)\n\n")
+	g.generateActions()
+	g.generateLexer()
+	g.generateTable(s.lexer+"PrefixSlots", "maps the first byte to prefix token slot", prefix)
+	g.generateTable(s.lexer+"InfixSlots", "maps the first byte to infix token slot", infix)
+	g.generateSkip()
+	g.generateExpected(s.lexer+"PrefixExpected", "is reported when no prefix token found", prefix)
+	g.generateExpected(s.lexer+"InfixExpected", "is reported when no infix token found", infix)
+	g.generateDispatch("PrefixToken", "parse.PrefixToken", prefix)
+	g.generateDispatch("InfixToken", "(parse.InfixToken, int)", infix)
+	g.generateTokens()
+	return format.Source(g.buf.Bytes())
+}
+
+// goString quote the string, prefer raw string literal
+func goString(str string) string {
+	if strings.ContainsAny(str, "`\r\n") {
+		return strconv.Quote(str)
+	}
+	return "`" + str + "`"
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func (g *generator) actionsType() string {
+	return g.spec.lexer + "Actions"
+}
+
+func (g *generator) generateActions() {
+	s := g.spec
+	g.printf("// %s build the values for %s\n", g.actionsType(), s.lexer)
+	g.printf("type %s interface {\n", g.actionsType())
+	for _, atom := range s.atoms {
+		g.printf("%s(src *parse.Source) interface{}\n", exported(atom.name))
+	}
+	for _, token := range s.tokens {
+		if token.prefix != nil {
+			g.printf("Prefix%s(right interface{}) interface{}\n", exported(token.name))
+		}
+		if token.infix == nil {
+			continue
+		}
+		if token.infix.kind == "postfix" {
+			g.printf("Postfix%s(left interface{}) interface{}\n", exported(token.name))
+		} else {
+			g.printf("Infix%s(left interface{}, right interface{}) interface{}\n", exported(token.name))
+		}
+	}
+	g.printf("}\n\n")
+}
+
+// tokenNames returns all of the token names in the order of definition
+func (g *generator) tokenNames() []string {
+	var names []string
+	for _, atom := range g.spec.atoms {
+		names = append(names, atom.name)
+	}
+	for _, group := range g.spec.groups {
+		names = append(names, group.name)
+	}
+	for _, token := range g.spec.tokens {
+		names = append(names, token.name)
+	}
+	return names
+}
+
+func (g *generator) generateLexer() {
+	s := g.spec
+	constructor := "New" + s.lexer
+	if s.lexer != exported(s.lexer) {
+		constructor = "new" + exported(s.lexer)
+	}
+	g.printf("type %s struct {\n", s.lexer)
+	g.printf("actions %s\n", g.actionsType())
+	for _, name := range g.tokenNames() {
+		g.printf("%s *%sToken\n", name, name)
+	}
+	g.printf("}\n\n")
+	g.printf("func %s(actions %s) *%s {\n", constructor, g.actionsType(), s.lexer)
+	g.printf("lexer := &%s{actions: actions}\n", s.lexer)
+	for _, name := range g.tokenNames() {
+		g.printf("lexer.%s = &%sToken{lexer: lexer}\n", name, name)
+	}
+	g.printf("return lexer\n}\n\n")
+	g.printf("func (lexer *%s) Parse(src *parse.Source, precedence int) interface{} {\n", s.lexer)
+	g.printf("return parse.Parse(src, lexer, precedence)\n}\n\n")
+	g.printf("// %sMatch tells if the source starts with the literal, the cursor is not moved\n", s.lexer)
+	g.printf("func %sMatch(src *parse.Source, literal string) bool {\n", s.lexer)
+	g.printf("if src.Error() != nil {\nreturn false\n}\n")
+	g.printf("src.StoreSavepoint()\n")
+	g.printf("matched := string(src.PeekN(len(literal))) == literal\n")
+	g.printf("src.RollbackToSavepoint()\n")
+	g.printf("return matched\n}\n\n")
+	g.printf("// %sFailed tells if the operand failed to parse, reaching the end of input is not failure\n", s.lexer)
+	g.printf("func %sFailed(src *parse.Source, operand interface{}) bool {\n", s.lexer)
+	g.printf("switch src.Error() {\ncase nil, io.EOF, io.ErrUnexpectedEOF:\nreturn operand == nil\n}\n")
+	g.printf("return true\n}\n\n")
+}
+
+func (g *generator) generateTable(name string, doc string, table *dispatchTable) {
+	g.printf("// %s %s\n", name, doc)
+	g.printf("var %s = [256]uint8{\n", name)
+	for row := 0; row < 16; row++ {
+		for col := 0; col < 16; col++ {
+			g.printf("%d, ", table.slotOf[row*16+col])
+		}
+		g.printf("// 0x%X0-0x%XF\n", row, row)
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) generateSkip() {
+	s := g.spec
+	g.printf("// %sSkip is the bytes skipped before every token\n", s.lexer)
+	g.printf("var %sSkip = [256]bool{", s.lexer)
+	for _, b := range s.skip {
+		g.printf("%q: true, ", b)
+	}
+	g.printf("}\n\n")
+	g.printf("func (lexer *%s) skip(src *parse.Source) {\n", s.lexer)
+	g.printf("for src.Error() == nil && %sSkip[src.Peek1()] {\nsrc.Read1()\n}\n}\n\n", s.lexer)
+}
+
+func (g *generator) generateExpected(name string, doc string, table *dispatchTable) {
+	g.printf("// %s %s\n", name, doc)
+	g.printf("var %s = []string{", name)
+	for _, label := range table.expected() {
+		g.printf("%s, ", goString(label))
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) generateDispatch(method string, returnType string, table *dispatchTable) {
+	s := g.spec
+	isInfix := method == "InfixToken"
+	notFound := "nil"
+	if isInfix {
+		notFound = "nil, 0"
+	}
+	expected := s.lexer + strings.TrimSuffix(method, "Token") + "Expected"
+	g.printf("func (lexer *%s) %s(src *parse.Source) %s {\n", s.lexer, method, returnType)
+	g.printf("lexer.skip(src)\n")
+	g.printf("b := src.Peek1()\n")
+	g.printf("if src.Error() != nil {\nsrc.ReportExpected(%s...)\nreturn %s\n}\n", expected, notFound)
+	g.printf("switch %s[b] {\n", s.lexer+strings.TrimSuffix(method, "Token")+"Slots")
+	for i, slot := range table.slots {
+		if i == 0 {
+			continue
+		}
+		g.printf("case %d:\n", i)
+		for _, candidate := range slot {
+			result := "lexer." + candidate.name
+			if isInfix {
+				result = fmt.Sprintf("lexer.%s, %d", candidate.name, candidate.precedence)
+			}
+			if len(candidate.literal) <= 1 {
+				g.printf("return %s\n", result)
+				break
+			}
+			g.printf("if %sMatch(src, %q) {\nreturn %s\n}\n", s.lexer, candidate.literal, result)
+		}
+	}
+	g.printf("}\nsrc.ReportExpected(%s...)\nreturn %s\n}\n\n", expected, notFound)
+}
+
+func (g *generator) generateTokens() {
+	s := g.spec
+	for _, atom := range s.atoms {
+		g.printf("type %sToken struct {\nlexer *%s\n}\n\n", atom.name, s.lexer)
+		g.printf("func (token *%sToken) PrefixParse(src *parse.Source) interface{} {\n", atom.name)
+		g.printf("return token.lexer.actions.%s(src)\n}\n\n", exported(atom.name))
+	}
+	for _, group := range s.groups {
+		g.printf("type %sToken struct {\nlexer *%s\n}\n\n", group.name, s.lexer)
+		g.printf("func (token *%sToken) PrefixParse(src *parse.Source) interface{} {\n", group.name)
+		g.printf("src.ReadN(%d) // %q\n", len(group.open), group.open)
+		g.printf("inner := token.lexer.Parse(src, 0)\n")
+		g.printf("if %sFailed(src, inner) {\nreturn nil\n}\n", s.lexer)
+		g.printf("token.lexer.skip(src)\n")
+		g.printf("if !%sMatch(src, %q) {\n", s.lexer, group.close)
+		g.printf("src.ReportExpected(%s)\n", goString(strconv.Quote(group.close)))
+		g.printf("src.ReportError(src.ExpectedError())\nreturn nil\n}\n")
+		g.printf("src.ReadN(%d) // %q\n", len(group.close), group.close)
+		g.printf("return inner\n}\n\n")
+	}
+	for _, token := range s.tokens {
+		g.printf("type %sToken struct {\nlexer *%s\n}\n\n", token.name, s.lexer)
+		if token.prefix != nil {
+			g.printf("func (token *%sToken) PrefixParse(src *parse.Source) interface{} {\n", token.name)
+			g.printf("src.ReadN(%d) // %q\n", len(token.literal), token.literal)
+			g.printf("right := token.lexer.Parse(src, %d)\n", token.prefix.precedence)
+			g.printf("if %sFailed(src, right) {\nreturn nil\n}\n", s.lexer)
+			g.printf("return token.lexer.actions.Prefix%s(right)\n}\n\n", exported(token.name))
+		}
+		if token.infix == nil {
+			continue
+		}
+		g.printf("func (token *%sToken) InfixParse(src *parse.Source, left interface{}) interface{} {\n", token.name)
+		g.printf("src.ReadN(%d) // %q\n", len(token.literal), token.literal)
+		switch token.infix.kind {
+		case "postfix":
+			g.printf("return token.lexer.actions.Postfix%s(left)\n}\n\n", exported(token.name))
+			continue
+		case "right":
+			g.printf("right := token.lexer.Parse(src, %d)\n", token.infix.precedence-1)
+		default:
+			g.printf("right := token.lexer.Parse(src, %d)\n", token.infix.precedence)
+		}
+		g.printf("if %sFailed(src, right) {\nreturn nil\n}\n", s.lexer)
+		g.printf("return token.lexer.actions.Infix%s(left, right)\n}\n\n", exported(token.name))
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate_Golden(t *testing.T) {
+	specFiles, err := filepath.Glob("testdata/*.parsegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, specFile := range specFiles {
+		t.Run(filepath.Base(specFile), test.Case(func(ctx context.Context) {
+			text := must.Call(ioutil.ReadFile, specFile)[0].([]byte)
+			s := must.Call(parseSpec, specFile, string(text))[0].(*spec)
+			code := must.Call(generate, s, filepath.Base(specFile))[0].([]byte)
+			goldenFile := strings.TrimSuffix(specFile, ".parsegen") + ".golden"
+			if *update {
+				must.Nil(ioutil.WriteFile(goldenFile, code, 0644))
+			}
+			golden := must.Call(ioutil.ReadFile, goldenFile)[0].([]byte)
+			must.Equal(string(golden), string(code))
+		}))
+	}
+}
+
+func TestGenerate_Dispatch(t *testing.T) {
+	t.Run("dispatch conflict", test.Case(func(ctx context.Context) {
+		s := must.Call(parseSpec, "conflict.parsegen", `
+			package calc
+			lexer exprLexer
+			token minus '-'
+			atom  value '0'-'9' '-'
+			prefix minus 6
+		`)[0].(*spec)
+		_, err := generate(s, "conflict.parsegen")
+		must.Equal(`byte '-' is dispatched to both minus and value`, err.Error())
+	}))
+	t.Run("longest literal first", test.Case(func(ctx context.Context) {
+		s := must.Call(parseSpec, "longest.parsegen", `
+			package calc
+			lexer exprLexer
+			token assign '='
+			token equal '=='
+			atom  value *
+			left  assign 1
+			left  equal 2
+		`)[0].(*spec)
+		code := string(must.Call(generate, s, "longest.parsegen")[0].([]byte))
+		must.Equal(true, strings.Contains(code, `if exprLexerMatch(src, "==") {
+			return lexer.equal, 2
+		}
+		return lexer.assign, 1`))
+	}))
+	t.Run("operand failure checked before action", test.Case(func(ctx context.Context) {
+		s := must.Call(parseSpec, "operand.parsegen", `
+			package calc
+			lexer exprLexer
+			token minus '-'
+			group paren '(' ')'
+			atom  value '0'-'9'
+			prefix minus 6
+			left  minus 3
+		`)[0].(*spec)
+		code := string(must.Call(generate, s, "operand.parsegen")[0].([]byte))
+		must.Equal(3, strings.Count(code, "if exprLexerFailed(src, "))
+		must.Equal(true, strings.Contains(code, `right := token.lexer.Parse(src, 3)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.InfixMinus(left, right)`))
+	}))
+}
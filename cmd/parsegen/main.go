@@ -0,0 +1,53 @@
+// Command parsegen generate pratt lexer and tokens from a grammar spec.
+// The generated lexer dispatch tokens by 256-entry first byte tables,
+// values are built by the actions interface implemented by user.
+//
+// It is usually invoked by go generate:
+//
+//	//go:generate parsegen -o calc_lexer.go calc.parsegen
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	output := flag.String("o", "", "output file, defaults to the spec file name with _lexer.go suffix")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: parsegen [-o output] spec")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(specFile string, output string) error {
+	text, err := ioutil.ReadFile(specFile)
+	if err != nil {
+		return err
+	}
+	s, err := parseSpec(specFile, string(text))
+	if err != nil {
+		return err
+	}
+	code, err := generate(s, filepath.Base(specFile))
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = strings.TrimSuffix(specFile, filepath.Ext(specFile)) + "_lexer.go"
+	}
+	return ioutil.WriteFile(output, code, 0644)
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// spec describes the lexer to generate, one directive per line:
+//
+//	package calc             # package of the generated file
+//	lexer   exprLexer        # name of the lexer struct
+//	skip    ' ' '\t'         # bytes skipped before every token
+//	token   plus '+'         # token literal
+//	atom    value '0'-'9'    # prefix token calling actions.Value(src), * is for any other byte
+//	group   group '(' ')'    # prefix token parsing the inner expression
+//	prefix  minus 6          # prefix operator with precedence
+//	left    plus 3           # left associative infix operator with precedence
+//	right   power 5          # right associative infix operator with precedence
+//	postfix bang 7           # postfix operator with precedence
+type spec struct {
+	pkg    string
+	lexer  string
+	skip   []byte
+	tokens []*tokenSpec
+	atoms  []*atomSpec
+	groups []*groupSpec
+}
+
+type tokenSpec struct {
+	name    string
+	literal string
+	prefix  *operatorSpec
+	infix   *operatorSpec
+}
+
+type atomSpec struct {
+	name      string
+	bytes     []byte
+	isDefault bool
+}
+
+type groupSpec struct {
+	name  string
+	open  string
+	close string
+}
+
+type operatorSpec struct {
+	kind       string
+	token      *tokenSpec
+	precedence int
+}
+
+type specParser struct {
+	filename string
+	lineNo   int
+	spec     *spec
+	names    map[string]int
+	tokens   map[string]*tokenSpec
+	errors   []string
+}
+
+func parseSpec(filename string, text string) (*spec, error) {
+	parser := &specParser{
+		filename: filename,
+		spec:     &spec{},
+		names:    map[string]int{},
+		tokens:   map[string]*tokenSpec{},
+	}
+	for i, line := range strings.Split(text, "\n") {
+		parser.lineNo = i + 1
+		fields, err := splitFields(line)
+		if err != nil {
+			parser.errorf("%s", err.Error())
+			continue
+		}
+		if len(fields) > 0 {
+			parser.parseDirective(fields)
+		}
+	}
+	parser.lineNo = 0
+	hasErrors := len(parser.errors) > 0
+	for _, token := range parser.spec.tokens {
+		if !hasErrors && token.prefix == nil && token.infix == nil {
+			parser.errorf("token %s is not used as operator", token.name)
+		}
+	}
+	if parser.spec.pkg == "" {
+		parser.errorf("missing package directive")
+	}
+	if parser.spec.lexer == "" {
+		parser.errorf("missing lexer directive")
+	}
+	if len(parser.errors) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(parser.errors, "\n"))
+	}
+	return parser.spec, nil
+}
+
+func (parser *specParser) errorf(format string, args ...interface{}) {
+	pos := parser.filename
+	if parser.lineNo > 0 {
+		pos = fmt.Sprintf("%s:%d", parser.filename, parser.lineNo)
+	}
+	parser.errors = append(parser.errors, pos+": "+fmt.Sprintf(format, args...))
+}
+
+// field is either a bare word or a quoted literal
+type field struct {
+	text   string
+	quoted bool
+}
+
+// splitFields split the line by space, # starts a comment
+func splitFields(line string) ([]field, error) {
+	var fields []field
+	for {
+		line = strings.TrimLeft(line, " \t\r")
+		if line == "" || line[0] == '#' {
+			return fields, nil
+		}
+		if line[0] != '\'' {
+			end := strings.IndexAny(line, " \t\r#'")
+			if end == -1 {
+				end = len(line)
+			}
+			fields = append(fields, field{text: line[:end]})
+			line = line[end:]
+			continue
+		}
+		end := 1
+		for end < len(line) && line[end] != '\'' {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(line) {
+			return nil, fmt.Errorf("literal not terminated")
+		}
+		inner := strings.Replace(line[1:end], `"`, `\"`, -1)
+		inner = strings.Replace(inner, `\'`, `'`, -1)
+		literal, err := strconv.Unquote(`"` + inner + `"`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid literal %s", line[:end+1])
+		}
+		fields = append(fields, field{text: literal, quoted: true})
+		line = line[end+1:]
+	}
+}
+
+var directiveArgs = map[string]int{
+	"package": 1, "lexer": 1, "token": 2, "group": 3,
+	"prefix": 2, "left": 2, "right": 2, "postfix": 2,
+}
+
+func (parser *specParser) parseDirective(fields []field) {
+	directive, args := fields[0].text, fields[1:]
+	expected, found := directiveArgs[directive]
+	if found && len(args) != expected {
+		parser.errorf("%s expects %d arguments", directive, expected)
+		return
+	}
+	switch directive {
+	case "package":
+		parser.spec.pkg = parser.name(args[0], false)
+	case "lexer":
+		parser.spec.lexer = parser.name(args[0], false)
+	case "skip":
+		for _, arg := range args {
+			parser.spec.skip = append(parser.spec.skip, parser.byteLiteral(arg))
+		}
+	case "token":
+		token := &tokenSpec{name: parser.name(args[0], true), literal: parser.literal(args[1])}
+		parser.spec.tokens = append(parser.spec.tokens, token)
+		parser.tokens[token.name] = token
+	case "atom":
+		if len(args) < 2 {
+			parser.errorf("atom expects name and bytes")
+			return
+		}
+		atom := &atomSpec{name: parser.name(args[0], true)}
+		for i := 1; i < len(args); i++ {
+			if !args[i].quoted && args[i].text == "*" {
+				atom.isDefault = true
+				continue
+			}
+			low := parser.byteLiteral(args[i])
+			high := low
+			if i+2 < len(args) && !args[i+1].quoted && args[i+1].text == "-" {
+				high = parser.byteLiteral(args[i+2])
+				i += 2
+			}
+			for b := int(low); b <= int(high); b++ {
+				atom.bytes = append(atom.bytes, byte(b))
+			}
+		}
+		parser.spec.atoms = append(parser.spec.atoms, atom)
+	case "group":
+		parser.spec.groups = append(parser.spec.groups, &groupSpec{
+			name:  parser.name(args[0], true),
+			open:  parser.literal(args[1]),
+			close: parser.literal(args[2]),
+		})
+	case "prefix", "left", "right", "postfix":
+		token := parser.tokens[args[0].text]
+		if token == nil {
+			parser.errorf("undefined token %s", args[0].text)
+			return
+		}
+		precedence, err := strconv.Atoi(args[1].text)
+		if err != nil || precedence <= 0 {
+			parser.errorf("invalid precedence %s", args[1].text)
+			return
+		}
+		operator := &operatorSpec{kind: directive, token: token, precedence: precedence}
+		if directive == "prefix" {
+			if token.prefix != nil {
+				parser.errorf("token %s is already a prefix operator", token.name)
+			}
+			token.prefix = operator
+		} else {
+			if token.infix != nil {
+				parser.errorf("token %s is already an infix operator", token.name)
+			}
+			token.infix = operator
+		}
+	default:
+		parser.errorf("unknown directive %s", directive)
+	}
+}
+
+// reservedNames are used by the generated lexer
+var reservedNames = map[string]bool{"actions": true, "skip": true}
+
+// name check the identifier, token names should be unique
+func (parser *specParser) name(arg field, isToken bool) string {
+	if arg.quoted || !isIdentifier(arg.text) {
+		parser.errorf("invalid name %s", arg.text)
+		return arg.text
+	}
+	if isToken {
+		if reservedNames[arg.text] || token.Lookup(arg.text).IsKeyword() {
+			parser.errorf("%s is reserved", arg.text)
+		}
+		if line := parser.names[arg.text]; line != 0 {
+			parser.errorf("%s already defined at line %d", arg.text, line)
+		}
+		parser.names[arg.text] = parser.lineNo
+	}
+	return arg.text
+}
+
+func isIdentifier(name string) bool {
+	for i, c := range name {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !isLetter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func (parser *specParser) literal(arg field) string {
+	if !arg.quoted || arg.text == "" {
+		parser.errorf("expected quoted literal, found %s", arg.text)
+	}
+	return arg.text
+}
+
+func (parser *specParser) byteLiteral(arg field) byte {
+	literal := parser.literal(arg)
+	if len(literal) != 1 {
+		parser.errorf("expected single byte literal, found %q", literal)
+		return 0
+	}
+	return literal[0]
+}
+
+// candidate is a token can be dispatched by the first byte
+type candidate struct {
+	literal string // empty for atom
+	name    string
+	// precedence of infix operator
+	precedence int
+}
+
+// dispatchTable maps the first byte to slot, slot 0 is for no candidate.
+// Tokens sharing the same first byte are put into one slot, tried by longest literal first.
+type dispatchTable struct {
+	slotOf [256]int
+	slots  [][]candidate
+}
+
+// dispatch build the first byte tables for prefix and infix tokens
+func (s *spec) dispatch() (prefix *dispatchTable, infix *dispatchTable, err error) {
+	var prefixByByte, infixByByte [256][]candidate
+	var defaultAtom *atomSpec
+	for _, atom := range s.atoms {
+		if atom.isDefault {
+			if defaultAtom != nil {
+				return nil, nil, fmt.Errorf("both %s and %s are default atom", defaultAtom.name, atom.name)
+			}
+			defaultAtom = atom
+		}
+		for _, b := range atom.bytes {
+			prefixByByte[b] = append(prefixByByte[b], candidate{name: atom.name})
+		}
+	}
+	for _, group := range s.groups {
+		b := group.open[0]
+		prefixByByte[b] = append(prefixByByte[b], candidate{literal: group.open, name: group.name})
+	}
+	for _, token := range s.tokens {
+		b := token.literal[0]
+		if token.prefix != nil {
+			prefixByByte[b] = append(prefixByByte[b], candidate{literal: token.literal, name: token.name})
+		}
+		if token.infix != nil {
+			infixByByte[b] = append(infixByByte[b], candidate{
+				literal: token.literal, name: token.name, precedence: token.infix.precedence})
+		}
+	}
+	if defaultAtom != nil {
+		for b := range prefixByByte {
+			if len(prefixByByte[b]) == 0 {
+				prefixByByte[b] = []candidate{{name: defaultAtom.name}}
+			}
+		}
+	}
+	if prefix, err = newDispatchTable(prefixByByte); err != nil {
+		return nil, nil, err
+	}
+	if infix, err = newDispatchTable(infixByByte); err != nil {
+		return nil, nil, err
+	}
+	return prefix, infix, nil
+}
+
+func newDispatchTable(byByte [256][]candidate) (*dispatchTable, error) {
+	table := &dispatchTable{slots: [][]candidate{nil}}
+	for b, candidates := range byByte {
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return len(candidates[i].literal) > len(candidates[j].literal)
+		})
+		for i := 1; i < len(candidates); i++ {
+			previous, current := candidates[i-1], candidates[i]
+			if previous.literal == current.literal || len(previous.literal) <= 1 && len(current.literal) <= 1 {
+				return nil, fmt.Errorf("byte %q is dispatched to both %s and %s", b, previous.name, current.name)
+			}
+		}
+		slot := table.find(candidates)
+		if slot == 0 {
+			slot = len(table.slots)
+			table.slots = append(table.slots, candidates)
+		}
+		table.slotOf[b] = slot
+	}
+	return table, nil
+}
+
+// expected lists the candidates for error reporting, literals are quoted, atoms are named
+func (table *dispatchTable) expected() []string {
+	var labels []string
+	seen := map[string]bool{}
+	for _, slot := range table.slots {
+		for _, candidate := range slot {
+			label := candidate.name
+			if candidate.literal != "" {
+				label = strconv.Quote(candidate.literal)
+			}
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// find the slot having the same candidates, 0 if not found
+func (table *dispatchTable) find(candidates []candidate) int {
+	for i := 1; i < len(table.slots); i++ {
+		slot := table.slots[i]
+		if len(slot) != len(candidates) {
+			continue
+		}
+		same := true
+		for j := range slot {
+			if slot[j] != candidates[j] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return i
+		}
+	}
+	return 0
+}
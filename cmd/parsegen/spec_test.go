@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func specError(text string) string {
+	_, err := parseSpec("test.parsegen", text)
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestParseSpec(t *testing.T) {
+	t.Run("literals", test.Case(func(ctx context.Context) {
+		s := must.Call(parseSpec, "test.parsegen", `
+			package calc # comment
+			lexer exprLexer
+			skip ' ' '\t' '\''
+			token power '**'
+			atom value '0'-'9' '.'
+			left power 5
+		`)[0].(*spec)
+		must.Equal("calc", s.pkg)
+		must.Equal([]byte{' ', '\t', '\''}, s.skip)
+		must.Equal("**", s.tokens[0].literal)
+		must.Equal([]byte("0123456789."), s.atoms[0].bytes)
+	}))
+	t.Run("missing directives", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen: missing package directive\ntest.parsegen: missing lexer directive",
+			specError(""))
+	}))
+	t.Run("undefined token", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen:3: undefined token plus",
+			specError("package calc\nlexer exprLexer\nleft plus 3"))
+	}))
+	t.Run("redefined name", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen:4: plus already defined at line 3",
+			specError("package calc\nlexer exprLexer\ntoken plus '+'\ntoken plus '-'\nleft plus 1"))
+	}))
+	t.Run("unused token", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen: token plus is not used as operator",
+			specError("package calc\nlexer exprLexer\ntoken plus '+'"))
+	}))
+	t.Run("reserved name", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen:3: skip is reserved",
+			specError("package calc\nlexer exprLexer\natom skip *"))
+	}))
+	t.Run("invalid precedence", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen:4: invalid precedence high",
+			specError("package calc\nlexer exprLexer\ntoken plus '+'\nleft plus high"))
+	}))
+	t.Run("literal not terminated", test.Case(func(ctx context.Context) {
+		must.Equal("test.parsegen:1: literal not terminated",
+			specError("skip '\npackage calc\nlexer exprLexer"))
+	}))
+}
@@ -0,0 +1,91 @@
+package parse
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// ColumnMode tells how the column of position is counted
+type ColumnMode int
+
+const (
+	// ColumnBytes counts the bytes, it is the default
+	ColumnBytes ColumnMode = iota
+	// ColumnRunes counts the unicode code points
+	ColumnRunes
+	// ColumnUTF16 counts the UTF-16 code units, as the language server protocol does
+	ColumnUTF16
+	// ColumnDisplay counts the cells in terminal, by grapheme cluster.
+	// East asian wide and emoji take 2 cells, combining marks and controls take none, tab takes 1.
+	ColumnDisplay
+)
+
+// WithColumnMode set how the column of position is counted, default to ColumnBytes
+func WithColumnMode(mode ColumnMode) SourceOption {
+	return func(src *Source) {
+		src.columns = mode
+	}
+}
+
+// ColumnMode returns how the column of position is counted
+func (src *Source) ColumnMode() ColumnMode {
+	return src.columns
+}
+
+// width counts the columns taken by the bytes
+func (mode ColumnMode) width(data []byte) int {
+	switch mode {
+	case ColumnRunes:
+		return utf8.RuneCount(data)
+	case ColumnUTF16:
+		units := 0
+		for len(data) > 0 {
+			r, n := utf8.DecodeRune(data)
+			units++
+			if r >= 0x10000 {
+				units++
+			}
+			data = data[n:]
+		}
+		return units
+	case ColumnDisplay:
+		cells := 0
+		for len(data) > 0 {
+			n := graphemeLen(data)
+			cells += displayWidth(data[:n])
+			data = data[n:]
+		}
+		return cells
+	}
+	return len(data)
+}
+
+var emojiPresentation = []byte("\uFE0F")
+
+// displayWidth counts the cells of one grapheme cluster
+func displayWidth(cluster []byte) int {
+	r, n := utf8.DecodeRune(cluster)
+	switch {
+	case r == '\t':
+		return 1
+	case r < 0x20 || 0x7F <= r && r < 0xA0:
+		return 0
+	case r < utf8.RuneSelf:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case 0x1F1E6 <= r && r <= 0x1F1FF:
+		// regional indicators are rendered as flag
+		return 2
+	case bytes.Contains(cluster[n:], emojiPresentation):
+		return 2
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,52 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_ColumnMode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		columns map[parse.ColumnMode]int
+	}{
+		{"ascii", "ab", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 3, parse.ColumnRunes: 3, parse.ColumnUTF16: 3, parse.ColumnDisplay: 3}},
+		{"combining mark", "e\u0301", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 4, parse.ColumnRunes: 3, parse.ColumnUTF16: 3, parse.ColumnDisplay: 2}},
+		{"cjk wide", "中文", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 7, parse.ColumnRunes: 3, parse.ColumnUTF16: 3, parse.ColumnDisplay: 5}},
+		{"emoji zwj sequence", "\U0001F468\u200D\U0001F469", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 12, parse.ColumnRunes: 4, parse.ColumnUTF16: 6, parse.ColumnDisplay: 3}},
+		{"flag", "\U0001F1EF\U0001F1F5", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 9, parse.ColumnRunes: 3, parse.ColumnUTF16: 5, parse.ColumnDisplay: 3}},
+		{"emoji presentation", "\u2764\uFE0F", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 7, parse.ColumnRunes: 3, parse.ColumnUTF16: 3, parse.ColumnDisplay: 3}},
+		{"tab", "\t", map[parse.ColumnMode]int{
+			parse.ColumnBytes: 2, parse.ColumnRunes: 2, parse.ColumnUTF16: 2, parse.ColumnDisplay: 2}},
+	}
+	for _, testCase := range testCases {
+		for mode, column := range testCase.columns {
+			testCase, mode, column := testCase, mode, column
+			t.Run(testCase.name, test.Case(func(ctx context.Context) {
+				src := must.Call(parse.NewSourceString, "x\n"+testCase.input+"!",
+					parse.WithColumnMode(mode))[0].(*parse.Source)
+				src.ReadN(2 + len(testCase.input))
+				must.Equal(mode, src.ColumnMode())
+				must.Equal(parse.Position{Offset: 2 + len(testCase.input), Line: 2, Column: column}, src.Position())
+			}))
+		}
+	}
+	t.Run("syntax error reports display column", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "中文]",
+			parse.WithColumnMode(parse.ColumnDisplay))[0].(*parse.Source)
+		src.ReadN(6)
+		src.ReportExpected("number")
+		must.Equal(`1:5: expected number; found "]"`, src.ExpectedError().Error())
+	}))
+}
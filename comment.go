@@ -0,0 +1,121 @@
+package parse
+
+import "fmt"
+
+// Comment is the syntax of comment, the brackets and quotes inside are not counted
+type Comment struct {
+	// Start begins the comment, like "//" or "/*"
+	Start string
+	// End finishes the comment, empty means to the end of line
+	End string
+	// Nested allows comment inside comment, like (* *) of OCaml
+	Nested bool
+}
+
+// The comment syntaxes of popular languages, the first matching one wins
+var (
+	// CComments is // and /* */ of C, Go and Java
+	CComments = []Comment{{Start: "//"}, {Start: "/*", End: "*/"}}
+	// ShellComments is # of shell, Python and YAML
+	ShellComments = []Comment{{Start: "#"}}
+	// SQLComments is -- and /* */ of SQL
+	SQLComments = []Comment{{Start: "--"}, {Start: "/*", End: "*/"}}
+	// LuaComments is --[[ ]] and -- of Lua, the long brackets with level like --[==[ are not supported
+	LuaComments = []Comment{{Start: "--[[", End: "]]"}, {Start: "--"}}
+	// OCamlComments is the nested (* *) of OCaml
+	OCamlComments = []Comment{{Start: "(*", End: "*)", Nested: true}}
+	// HTMLComments is <!-- --> of HTML and XML
+	HTMLComments = []Comment{{Start: "<!--", End: "-->"}}
+)
+
+// UnterminatedError is reported at the opening position, if the comment is not terminated before EOF
+type UnterminatedError struct {
+	Position
+	Start string
+	End   string
+}
+
+func (err *UnterminatedError) Error() string {
+	return fmt.Sprintf("%v: unterminated comment, expecting %q to close %q", err.Position, err.End, err.Start)
+}
+
+// SkipComments skip the consecutive comments, returns how many bytes skipped.
+// The first matching syntax wins, so the longer start should go first, like "--[[" before "--".
+// The line comment does not include the newline.
+// If the block comment is not terminated, nothing is skipped and *UnterminatedError is reported.
+func (src *Source) SkipComments(syntax []Comment) int {
+	if src.Error() != nil {
+		return 0
+	}
+	longest := 1
+	for _, comment := range syntax {
+		longest = maxLen(longest, comment.Start)
+	}
+	start := src.nextIdx
+	for {
+		comment := findComment(syntax, src.peekUpTo(longest))
+		if comment == nil {
+			break
+		}
+		if err := src.skipComment(*comment); err != nil {
+			src.nextIdx = start
+			src.ReportError(&UnterminatedError{Position: err.Opening, Start: err.Open, End: err.Close})
+			return 0
+		}
+		if src.Error() != nil {
+			return 0
+		}
+	}
+	if src.Error() != nil {
+		return 0
+	}
+	return src.nextIdx - start
+}
+
+func findComment(syntax []Comment, ahead []byte) *Comment {
+	for i := range syntax {
+		if hasPrefix(ahead, syntax[i].Start) {
+			return &syntax[i]
+		}
+	}
+	return nil
+}
+
+// skipComment skips the comment at cursor, including the end
+func (src *Source) skipComment(comment Comment) *UnbalancedError {
+	start := src.Offset()
+	src.ReadN(len(comment.Start))
+	if comment.End == "" {
+		// line comment, the newline is not skipped
+		for {
+			ahead := src.peekUpTo(1)
+			if src.Error() != nil || len(ahead) == 0 || ahead[0] == '\n' {
+				return nil
+			}
+			src.ReadN(1)
+		}
+	}
+	depth := 1
+	longest := maxLen(len(comment.End), comment.Start)
+	for {
+		ahead := src.peekUpTo(longest)
+		switch {
+		case src.Error() != nil:
+			// the caller checks the error condition
+			return nil
+		case len(ahead) == 0:
+			return &UnbalancedError{Position: src.Position(), Found: "EOF",
+				Open: comment.Start, Close: comment.End, Opening: src.PositionOf(start)}
+		case hasPrefix(ahead, comment.End):
+			src.ReadN(len(comment.End))
+			if depth--; depth == 0 {
+				return nil
+			}
+		case comment.Nested && hasPrefix(ahead, comment.Start):
+			src.ReadN(len(comment.Start))
+			depth++
+		default:
+			src.ReadN(1)
+		}
+	}
+}
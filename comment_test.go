@@ -0,0 +1,25 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_SkipComments(t *testing.T) {
+	t.Run("consecutive", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "(* a (* b *) *)(* c *)\n")[0].(*parse.Source)
+		must.Equal(22, src.SkipComments(parse.OCamlComments))
+		must.Nil(src.Error())
+	}))
+	t.Run("unterminated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "-- a\n--[[ b")[0].(*parse.Source)
+		src.ReadN(5)
+		must.Equal(0, src.SkipComments(parse.LuaComments))
+		must.Equal(5, src.Offset())
+		must.Equal(`2:1: unterminated comment, expecting "]]" to close "--[["`, src.Error().Error())
+	}))
+}
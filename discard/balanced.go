@@ -0,0 +1,11 @@
+package discard
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// Balanced discard from the opening bracket to the matching closing one, returns how many bytes discarded.
+// If the brackets are not balanced, nothing is discarded and *parse.UnbalancedError is reported.
+func Balanced(src *parse.Source, opts *parse.BalancedOptions) int {
+	return src.SkipBalanced(opts)
+}
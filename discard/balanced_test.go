@@ -0,0 +1,19 @@
+package discard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/discard"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestBalanced(t *testing.T) {
+	t.Run("skip opaque value", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, `[1, [2], "]"], 3`)[0].(*parse.Source)
+		must.Equal(13, discard.Balanced(src, nil))
+		must.Equal(", 3", string(src.ReadAll()))
+	}))
+}
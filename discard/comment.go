@@ -0,0 +1,24 @@
+package discard
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// Comments discard the consecutive comments, like parse.CComments, returns how many bytes discarded.
+// If the block comment is not terminated, nothing is discarded and *parse.UnterminatedError is reported.
+func Comments(src *parse.Source, syntax []parse.Comment) int {
+	return src.SkipComments(syntax)
+}
+
+// Trivia discard any mix of unicode spaces and comments, returns how many bytes discarded
+func Trivia(src *parse.Source, syntax []parse.Comment) int {
+	count := 0
+	for src.Error() == nil {
+		n := UnicodeSpace(src) + Comments(src, syntax)
+		if n == 0 {
+			break
+		}
+		count += n
+	}
+	return count
+}
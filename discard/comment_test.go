@@ -0,0 +1,32 @@
+package discard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/discard"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestComments(t *testing.T) {
+	t.Run("only comments", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "/* a */ x")[0].(*parse.Source)
+		must.Equal(7, discard.Comments(src, parse.CComments))
+		must.Equal(byte(' '), src.Peek1())
+	}))
+}
+
+func TestTrivia(t *testing.T) {
+	t.Run("mix of spaces and comments", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, " // a\n\t/* b */\n  # c\nx")[0].(*parse.Source)
+		must.Equal(21, discard.Trivia(src, append(parse.CComments, parse.ShellComments...)))
+		must.Equal(byte('x'), src.Peek1())
+	}))
+	t.Run("unterminated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "  /* a")[0].(*parse.Source)
+		discard.Trivia(src, parse.CComments)
+		must.Equal(`1:3: unterminated comment, expecting "*/" to close "/*"`, src.Error().Error())
+	}))
+}
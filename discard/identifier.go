@@ -0,0 +1,11 @@
+package discard
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// Identifier discard the identifier defined by options, nil options means UAX #31 default.
+// It returns how many bytes discarded, and whether the identifier is one of the keywords.
+func Identifier(src *parse.Source, opts *parse.IdentifierOptions) (int, bool) {
+	return src.SkipIdentifier(opts)
+}
@@ -0,0 +1,29 @@
+package discard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/discard"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestIdentifier(t *testing.T) {
+	t.Run("non-ascii", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "größe = 1")[0].(*parse.Source)
+		count, keyword := discard.Identifier(src, nil)
+		must.Equal(len("größe"), count)
+		must.Equal(false, keyword)
+		must.Equal(byte(' '), src.Peek1())
+	}))
+	t.Run("keyword", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "return")[0].(*parse.Source)
+		count, keyword := discard.Identifier(src, &parse.IdentifierOptions{
+			Keywords: map[string]bool{"return": true},
+		})
+		must.Equal(6, count)
+		must.Equal(true, keyword)
+	}))
+}
@@ -53,3 +53,15 @@ func TestRane(t *testing.T) {
 		must.Equal(7, count)
 	}))
 }
+
+func TestUnicodeRanges_UTF8Policy(t *testing.T) {
+	t.Run("strict", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("ab\xc0\x80c", parse.WithUTF8Policy(parse.UTF8Strict))
+		must.Equal(2, discard.UnicodeRanges(src, nil, []*unicode.RangeTable{unicode.Pattern_Syntax}))
+		must.Equal("1:3: invalid UTF-8 byte 0xc0", src.Error().Error())
+	}))
+	t.Run("pass through", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("ab\xc0\x80c")
+		must.Equal(5, discard.UnicodeRanges(src, nil, []*unicode.RangeTable{unicode.Pattern_Syntax}))
+	}))
+}
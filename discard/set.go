@@ -0,0 +1,38 @@
+package discard
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// InSet discard the bytes in set, returns how many bytes discarded
+func InSet(src *parse.Source, set *parse.ByteSet) int {
+	return skipUntil(src, func(chunk []byte) int {
+		if n := set.Span(chunk); n < len(chunk) {
+			return n
+		}
+		return -1
+	})
+}
+
+// UntilSet discard the bytes not in set, returns how many bytes discarded.
+// EOF is reported if none of the set found.
+func UntilSet(src *parse.Source, set *parse.ByteSet) int {
+	return skipUntil(src, set.Index)
+}
+
+// skipUntil discard the bytes before the end, the end returns -1 if not found in chunk
+func skipUntil(src *parse.Source, end func(chunk []byte) int) int {
+	count := 0
+	for src.Error() == nil {
+		chunk := src.Peek()
+		if i := end(chunk); i >= 0 {
+			src.ReadN(i)
+			return count + i
+		}
+		src.ReadN(len(chunk))
+		count += len(chunk)
+		// fill more bytes, EOF is reported if no more
+		src.Peek1()
+	}
+	return count
+}
@@ -0,0 +1,51 @@
+package discard_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/discard"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestInSet(t *testing.T) {
+	t.Run("across chunks", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("   \tx"), 2)[0].(*parse.Source)
+		must.Equal(4, discard.InSet(src, parse.NewByteSet(" \t")))
+		must.Equal(byte('x'), src.Peek1())
+	}))
+}
+
+func TestUntilSet(t *testing.T) {
+	t.Run("found", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("abc\r\n"), 2)[0].(*parse.Source)
+		must.Equal(3, discard.UntilSet(src, parse.NewByteSet("\r\n")))
+		must.Nil(src.Error())
+	}))
+	t.Run("not found", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("abc"), 2)[0].(*parse.Source)
+		must.Equal(3, discard.UntilSet(src, parse.NewByteSet("\r\n")))
+		must.NotNil(src.FatalError())
+	}))
+}
+
+var benchmarkSpaces = []byte(strings.Repeat(" \t\r\n", 1000) + "x")
+
+func BenchmarkRange(b *testing.B) {
+	target := []byte{'\t', '\n', '\v', '\f', '\r', ' '}
+	for i := 0; i < b.N; i++ {
+		src, _ := parse.NewSourceBytes(benchmarkSpaces)
+		discard.Range(src, target)
+	}
+}
+
+func BenchmarkInSet(b *testing.B) {
+	set := parse.NewByteSet("\t\n\v\f\r ")
+	for i := 0; i < b.N; i++ {
+		src, _ := parse.NewSourceBytes(benchmarkSpaces)
+		discard.InSet(src, set)
+	}
+}
@@ -21,7 +21,12 @@ func UnicodeSpace(src *parse.Source) int {
 	return count
 }
 
+var spaces = parse.NewByteSet("\t\n\v\f\r ")
+
 // Space reads consecutive space(\t \n \v \f \r ' ') and returns the space number
 func Space(src *parse.Source) int {
-	return Range(src, []byte{'\t', '\n', '\v', '\f', '\r', ' '})
+	if src == nil {
+		return 0
+	}
+	return InSet(src, spaces)
 }
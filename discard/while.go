@@ -0,0 +1,18 @@
+package discard
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// While discard the bytes matching the predicate, returns how many bytes discarded.
+// If fewer than repeat.Min matched, nothing is discarded and syntax error is reported.
+func While(src *parse.Source, pred func(b byte) bool, repeat *parse.Repeat) int {
+	return src.SkipWhile(pred, repeat)
+}
+
+// WhileRune discard the runes matching the predicate, returns how many runes discarded.
+// If fewer than repeat.Min matched, nothing is discarded and syntax error is reported.
+func WhileRune(src *parse.Source, pred func(r rune) bool, repeat *parse.Repeat) int {
+	_, count := src.SkipWhileRune(pred, repeat)
+	return count
+}
@@ -0,0 +1,25 @@
+package discard_test
+
+import (
+	"context"
+	"testing"
+	"unicode"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/discard"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestWhile(t *testing.T) {
+	t.Run("bytes", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "   x")[0].(*parse.Source)
+		must.Equal(3, discard.While(src, func(b byte) bool { return b == ' ' }, nil))
+		must.Equal(byte('x'), src.Peek1())
+	}))
+	t.Run("runes", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\u3000\u3000x")[0].(*parse.Source)
+		must.Equal(2, discard.WhileRune(src, unicode.IsSpace, &parse.Repeat{Min: 1}))
+		must.Equal(byte('x'), src.Peek1())
+	}))
+}
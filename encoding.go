@@ -0,0 +1,241 @@
+package parse
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Encoding of the input, it is transcoded to UTF-8 while filling the source
+type Encoding int
+
+const (
+	// UTF8 is not transcoded
+	UTF8 Encoding = iota
+	// UTF16LE is UTF-16 little endian
+	UTF16LE
+	// UTF16BE is UTF-16 big endian
+	UTF16BE
+	// UTF32LE is UTF-32 little endian
+	UTF32LE
+	// UTF32BE is UTF-32 big endian
+	UTF32BE
+	// Latin1 is ISO-8859-1
+	Latin1
+	// Windows1252 is the western european code page of windows
+	Windows1252
+)
+
+var byteOrderMarks = []struct {
+	encoding Encoding
+	bom      []byte
+}{
+	// UTF-32LE should be checked before UTF-16LE, they share the prefix
+	{UTF32LE, []byte{0xFF, 0xFE, 0x00, 0x00}},
+	{UTF32BE, []byte{0x00, 0x00, 0xFE, 0xFF}},
+	{UTF8, []byte{0xEF, 0xBB, 0xBF}},
+	{UTF16LE, []byte{0xFF, 0xFE}},
+	{UTF16BE, []byte{0xFE, 0xFF}},
+}
+
+// WithEncoding transcode the input from the encoding to UTF-8.
+// The byte order mark of the encoding is stripped if present.
+func WithEncoding(encoding Encoding) SourceOption {
+	return func(src *Source) {
+		src.transcoder = &transcoder{encoding: encoding, sniffing: true}
+	}
+}
+
+// WithBOMSniffing detect the encoding by byte order mark, and strip it.
+// If there is no byte order mark, the fallback encoding is used.
+func WithBOMSniffing(fallback Encoding) SourceOption {
+	return func(src *Source) {
+		src.transcoder = &transcoder{encoding: fallback, sniffing: true, anyBOM: true}
+	}
+}
+
+// Encoding returns the encoding of input, might be detected by byte order mark
+func (src *Source) Encoding() Encoding {
+	if src.transcoder == nil {
+		return UTF8
+	}
+	return src.transcoder.encoding
+}
+
+// OriginalOffset convert the offset in transcoded UTF-8 to the offset in original input.
+// The byte order mark is counted in original offset.
+func (src *Source) OriginalOffset(offset int) int {
+	if src.transcoder == nil {
+		return offset
+	}
+	marks := src.transcoder.marks
+	i := sort.Search(len(marks), func(i int) bool {
+		return marks[i].offset > offset
+	}) - 1
+	if i < 0 {
+		return src.transcoder.bomLen
+	}
+	mark := marks[i]
+	return mark.original + (offset-mark.offset)/mark.width*mark.originalWidth
+}
+
+// passThrough means the original bytes are already UTF-8
+const passThrough rune = -1
+
+// offsetMark starts a run of runes having same width in UTF-8 and original encoding
+type offsetMark struct {
+	offset        int
+	original      int
+	width         int
+	originalWidth int
+}
+
+// transcoder decode the original bytes, keeps the mapping of offsets
+type transcoder struct {
+	encoding Encoding
+	sniffing bool
+	anyBOM   bool
+	bomLen   int
+	// pending is the original bytes not decoded yet
+	pending []byte
+	// decoded counts the original bytes decoded
+	decoded int
+	marks   []offsetMark
+}
+
+// transcode the original bytes into readBytes, eof tells no more bytes will come
+func (src *Source) transcode(original []byte, eof bool) {
+	t := src.transcoder
+	t.pending = append(t.pending, original...)
+	if t.sniffing {
+		if len(t.pending) < 4 && !eof {
+			return
+		}
+		t.sniffBOM()
+	}
+	for len(t.pending) > 0 {
+		r, originalWidth := t.decodeRune(eof)
+		if originalWidth == 0 {
+			break
+		}
+		offset := src.base.Offset + len(src.readBytes)
+		if r == passThrough {
+			src.readBytes = append(src.readBytes, t.pending[:originalWidth]...)
+			t.mark(offset, 1, 1)
+		} else {
+			src.readBytes = append(src.readBytes, string(r)...)
+			t.mark(offset, utf8.RuneLen(r), originalWidth)
+		}
+		t.pending = t.pending[originalWidth:]
+		t.decoded += originalWidth
+	}
+}
+
+func (t *transcoder) sniffBOM() {
+	t.sniffing = false
+	for _, mark := range byteOrderMarks {
+		if (t.anyBOM || mark.encoding == t.encoding) && bytes.HasPrefix(t.pending, mark.bom) {
+			t.encoding = mark.encoding
+			t.bomLen = len(mark.bom)
+			t.pending = t.pending[t.bomLen:]
+			t.decoded = t.bomLen
+			return
+		}
+	}
+}
+
+// mark the rune at offset, the run of previous mark is extended if having same widths
+func (t *transcoder) mark(offset int, width int, originalWidth int) {
+	if len(t.marks) > 0 {
+		last := t.marks[len(t.marks)-1]
+		if last.width == width && last.originalWidth == originalWidth {
+			return
+		}
+	}
+	t.marks = append(t.marks, offsetMark{
+		offset: offset, original: t.decoded, width: width, originalWidth: originalWidth})
+}
+
+// decodeRune decode one rune from pending bytes, returns the width in original encoding.
+// Width 0 means more bytes needed. UTF-8 is not decoded, the rune is passThrough.
+func (t *transcoder) decodeRune(eof bool) (rune, int) {
+	p := t.pending
+	switch t.encoding {
+	case UTF8:
+		return passThrough, len(p)
+	case Latin1:
+		return rune(p[0]), 1
+	case Windows1252:
+		return charmap.Windows1252.DecodeByte(p[0]), 1
+	case UTF16LE, UTF16BE:
+		if len(p) < 2 {
+			return truncated(p, eof)
+		}
+		r1 := t.unit16(p)
+		if !utf16.IsSurrogate(r1) {
+			return r1, 2
+		}
+		if len(p) < 4 {
+			if eof {
+				return utf8.RuneError, len(p)
+			}
+			return 0, 0
+		}
+		if r := utf16.DecodeRune(r1, t.unit16(p[2:])); r != utf8.RuneError {
+			return r, 4
+		}
+		return utf8.RuneError, 2
+	case UTF32LE, UTF32BE:
+		if len(p) < 4 {
+			return truncated(p, eof)
+		}
+		var r rune
+		if t.encoding == UTF32LE {
+			r = rune(p[0]) | rune(p[1])<<8 | rune(p[2])<<16 | rune(p[3])<<24
+		} else {
+			r = rune(p[3]) | rune(p[2])<<8 | rune(p[1])<<16 | rune(p[0])<<24
+		}
+		if !utf8.ValidRune(r) {
+			r = utf8.RuneError
+		}
+		return r, 4
+	}
+	panic("unknown encoding")
+}
+
+func (t *transcoder) unit16(p []byte) rune {
+	if t.encoding == UTF16LE {
+		return rune(p[0]) | rune(p[1])<<8
+	}
+	return rune(p[1]) | rune(p[0])<<8
+}
+
+// fillTranscoded read from reader until some bytes transcoded, or error happened
+func (src *Source) fillTranscoded() error {
+	for {
+		n, err := 0, io.EOF
+		if src.reader != nil {
+			n, err = src.reader.Read(src.buf)
+		}
+		before := len(src.readBytes)
+		src.transcode(src.buf[:n], err != nil)
+		if len(src.readBytes) > before {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// truncated code unit at the end is replaced by utf8.RuneError
+func truncated(p []byte, eof bool) (rune, int) {
+	if eof {
+		return utf8.RuneError, len(p)
+	}
+	return 0, 0
+}
@@ -0,0 +1,76 @@
+package parse_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/iotest"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_Encoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		opt      parse.SourceOption
+		encoding parse.Encoding
+		output   string
+	}{
+		{"utf-16le with bom", "\xff\xfea\x00\xe9\x00", parse.WithBOMSniffing(parse.UTF8), parse.UTF16LE, "aé"},
+		{"utf-16be with bom", "\xfe\xff\x00a\x4e\x2d", parse.WithBOMSniffing(parse.UTF8), parse.UTF16BE, "a中"},
+		{"utf-32le with bom", "\xff\xfe\x00\x00a\x00\x00\x00\x00\xf6\x01\x00", parse.WithBOMSniffing(parse.UTF8), parse.UTF32LE, "a😀"},
+		{"utf-32be with bom", "\x00\x00\xfe\xff\x00\x00\x00a", parse.WithBOMSniffing(parse.UTF8), parse.UTF32BE, "a"},
+		{"utf-8 with bom", "\xef\xbb\xbfab", parse.WithBOMSniffing(parse.Latin1), parse.UTF8, "ab"},
+		{"fallback without bom", "a\xe9", parse.WithBOMSniffing(parse.Latin1), parse.Latin1, "aé"},
+		{"explicit utf-16be", "\x00a\x00b", parse.WithEncoding(parse.UTF16BE), parse.UTF16BE, "ab"},
+		{"explicit encoding strips its bom", "\xfe\xff\x00a", parse.WithEncoding(parse.UTF16BE), parse.UTF16BE, "a"},
+		{"surrogate pair", "\x3d\xd8\x00\xde", parse.WithEncoding(parse.UTF16LE), parse.UTF16LE, "😀"},
+		{"unpaired surrogate", "\x3d\xd8a\x00", parse.WithEncoding(parse.UTF16LE), parse.UTF16LE, "�a"},
+		{"truncated code unit", "a\x00b", parse.WithEncoding(parse.UTF16LE), parse.UTF16LE, "a�"},
+		{"invalid utf-32", "\x00\xd8\x00\x00", parse.WithEncoding(parse.UTF32LE), parse.UTF32LE, "�"},
+		{"windows-1252", "\x80\x93", parse.WithEncoding(parse.Windows1252), parse.Windows1252, "€“"},
+		{"latin-1", "\x80\xff", parse.WithEncoding(parse.Latin1), parse.Latin1, "\u0080ÿ"},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, testCase.input, testCase.opt)[0].(*parse.Source)
+			must.Equal(testCase.output, string(src.ReadAll()))
+			must.Equal(testCase.encoding, src.Encoding())
+		}))
+		t.Run(testCase.name+" byte by byte", test.Case(func(ctx context.Context) {
+			reader := iotest.OneByteReader(bytes.NewReader([]byte(testCase.input)))
+			src := must.Call(parse.NewSource, reader, 1, testCase.opt)[0].(*parse.Source)
+			must.Equal(testCase.output, string(src.ReadAll()))
+		}))
+		t.Run(testCase.name+" from bytes", test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceBytes, []byte(testCase.input), testCase.opt)[0].(*parse.Source)
+			must.Equal(testCase.output, string(src.ReadAll()))
+		}))
+	}
+}
+
+func TestSource_OriginalOffset(t *testing.T) {
+	t.Run("utf-16 with bom", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\xff\xfea\x00\xe9\x00\x3d\xd8\x00\xdeb\x00",
+			parse.WithBOMSniffing(parse.UTF8))[0].(*parse.Source)
+		must.Equal("aé😀b", string(src.ReadAll()))
+		must.Equal(2, src.OriginalOffset(0))
+		must.Equal(4, src.OriginalOffset(1))
+		must.Equal(6, src.OriginalOffset(3))
+		must.Equal(10, src.OriginalOffset(7))
+		must.Equal(12, src.OriginalOffset(8))
+	}))
+	t.Run("latin-1", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a\xe9b", parse.WithEncoding(parse.Latin1))[0].(*parse.Source)
+		src.ReadN(3)
+		must.Equal(2, src.OriginalOffset(src.Offset()))
+	}))
+	t.Run("not transcoded", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		must.Equal(2, src.OriginalOffset(2))
+	}))
+}
@@ -0,0 +1,90 @@
+// Package calc is the generated version of the hand written exprLexer in example/expr
+package calc
+
+//go:generate go run github.com/modern-go/parse/cmd/parsegen -o calc_lexer.go calc.parsegen
+
+import (
+	"errors"
+	"io"
+
+	"github.com/modern-go/parse"
+)
+
+var errDivisionByZero = errors.New("division by zero")
+
+// Eval evaluate the integer expression, the bad input like "1+" or "1/0" is error
+func Eval(input string) (int, error) {
+	src, err := parse.NewSourceString(input)
+	if err != nil {
+		return 0, err
+	}
+	value := newExprLexer(&intActions{src: src}).Parse(src, 0)
+	switch src.Error() {
+	case nil:
+		// stopped before the end
+		return 0, src.ExpectedError()
+	case io.ErrUnexpectedEOF:
+		if value != nil {
+			return value.(int), nil
+		}
+	}
+	return 0, src.Error()
+}
+
+// intActions reports the error to the source being evaluated
+type intActions struct {
+	src *parse.Source
+}
+
+func (actions *intActions) Value(src *parse.Source) interface{} {
+	value := 0
+	for src.Error() == nil {
+		b := src.Peek1()
+		if b < '0' || b > '9' {
+			break
+		}
+		src.Read1()
+		value = value*10 + int(b-'0')
+	}
+	return value
+}
+
+func (actions *intActions) InfixPlus(left interface{}, right interface{}) interface{} {
+	return left.(int) + right.(int)
+}
+
+func (actions *intActions) PrefixMinus(right interface{}) interface{} {
+	return -right.(int)
+}
+
+func (actions *intActions) InfixMinus(left interface{}, right interface{}) interface{} {
+	return left.(int) - right.(int)
+}
+
+func (actions *intActions) InfixMultiply(left interface{}, right interface{}) interface{} {
+	return left.(int) * right.(int)
+}
+
+func (actions *intActions) InfixDivide(left interface{}, right interface{}) interface{} {
+	if right.(int) == 0 {
+		actions.src.ReportError(errDivisionByZero)
+		return nil
+	}
+	return left.(int) / right.(int)
+}
+
+func (actions *intActions) InfixPower(left interface{}, right interface{}) interface{} {
+	result := 1
+	for i := 0; i < right.(int); i++ {
+		result *= left.(int)
+	}
+	return result
+}
+
+func (actions *intActions) PostfixBang(left interface{}) interface{} {
+	result := 1
+	for i := 2; i <= left.(int); i++ {
+		result *= i
+	}
+	return result
+}
@@ -0,0 +1,281 @@
+// Code generated by parsegen from calc.parsegen. DO NOT EDIT.
+
+package calc
+
+import (
+	"io"
+
+	"github.com/modern-go/parse"
+)
+
+// exprLexerActions build the values for exprLexer
+type exprLexerActions interface {
+	Value(src *parse.Source) interface{}
+	InfixPlus(left interface{}, right interface{}) interface{}
+	PrefixMinus(right interface{}) interface{}
+	InfixMinus(left interface{}, right interface{}) interface{}
+	InfixMultiply(left interface{}, right interface{}) interface{}
+	InfixDivide(left interface{}, right interface{}) interface{}
+	InfixPower(left interface{}, right interface{}) interface{}
+	PostfixBang(left interface{}) interface{}
+}
+
+type exprLexer struct {
+	actions  exprLexerActions
+	value    *valueToken
+	group    *groupToken
+	plus     *plusToken
+	minus    *minusToken
+	multiply *multiplyToken
+	divide   *divideToken
+	power    *powerToken
+	bang     *bangToken
+}
+
+func newExprLexer(actions exprLexerActions) *exprLexer {
+	lexer := &exprLexer{actions: actions}
+	lexer.value = &valueToken{lexer: lexer}
+	lexer.group = &groupToken{lexer: lexer}
+	lexer.plus = &plusToken{lexer: lexer}
+	lexer.minus = &minusToken{lexer: lexer}
+	lexer.multiply = &multiplyToken{lexer: lexer}
+	lexer.divide = &divideToken{lexer: lexer}
+	lexer.power = &powerToken{lexer: lexer}
+	lexer.bang = &bangToken{lexer: lexer}
+	return lexer
+}
+
+func (lexer *exprLexer) Parse(src *parse.Source, precedence int) interface{} {
+	return parse.Parse(src, lexer, precedence)
+}
+
+// exprLexerMatch tells if the source starts with the literal, the cursor is not moved
+func exprLexerMatch(src *parse.Source, literal string) bool {
+	if src.Error() != nil {
+		return false
+	}
+	src.StoreSavepoint()
+	matched := string(src.PeekN(len(literal))) == literal
+	src.RollbackToSavepoint()
+	return matched
+}
+
+// exprLexerFailed tells if the operand failed to parse, reaching the end of input is not failure
+func exprLexerFailed(src *parse.Source, operand interface{}) bool {
+	switch src.Error() {
+	case nil, io.EOF, io.ErrUnexpectedEOF:
+		return operand == nil
+	}
+	return true
+}
+
+// exprLexerPrefixSlots maps the first byte to prefix token slot
+var exprLexerPrefixSlots = [256]uint8{
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x00-0x0F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x10-0x1F
+	0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 2, 0, 0, // 0x20-0x2F
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 0, 0, 0, 0, 0, 0, // 0x30-0x3F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x40-0x4F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x50-0x5F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x60-0x6F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x70-0x7F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x80-0x8F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x90-0x9F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xA0-0xAF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xB0-0xBF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xC0-0xCF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xD0-0xDF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xE0-0xEF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xF0-0xFF
+}
+
+// exprLexerInfixSlots maps the first byte to infix token slot
+var exprLexerInfixSlots = [256]uint8{
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x00-0x0F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x10-0x1F
+	0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 0, 4, 0, 5, // 0x20-0x2F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x30-0x3F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x40-0x4F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x50-0x5F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x60-0x6F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x70-0x7F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x80-0x8F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0x90-0x9F
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xA0-0xAF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xB0-0xBF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xC0-0xCF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xD0-0xDF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xE0-0xEF
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 0xF0-0xFF
+}
+
+// exprLexerSkip is the bytes skipped before every token
+var exprLexerSkip = [256]bool{' ': true, '\t': true}
+
+func (lexer *exprLexer) skip(src *parse.Source) {
+	for src.Error() == nil && exprLexerSkip[src.Peek1()] {
+		src.Read1()
+	}
+}
+
+// exprLexerPrefixExpected is reported when no prefix token found
+var exprLexerPrefixExpected = []string{`"("`, `"-"`, `value`}
+
+// exprLexerInfixExpected is reported when no infix token found
+var exprLexerInfixExpected = []string{`"!"`, `"**"`, `"*"`, `"+"`, `"-"`, `"/"`}
+
+func (lexer *exprLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	lexer.skip(src)
+	b := src.Peek1()
+	if src.Error() != nil {
+		src.ReportExpected(exprLexerPrefixExpected...)
+		return nil
+	}
+	switch exprLexerPrefixSlots[b] {
+	case 1:
+		return lexer.group
+	case 2:
+		return lexer.minus
+	case 3:
+		return lexer.value
+	}
+	src.ReportExpected(exprLexerPrefixExpected...)
+	return nil
+}
+
+func (lexer *exprLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	lexer.skip(src)
+	b := src.Peek1()
+	if src.Error() != nil {
+		src.ReportExpected(exprLexerInfixExpected...)
+		return nil, 0
+	}
+	switch exprLexerInfixSlots[b] {
+	case 1:
+		return lexer.bang, 7
+	case 2:
+		if exprLexerMatch(src, "**") {
+			return lexer.power, 5
+		}
+		return lexer.multiply, 4
+	case 3:
+		return lexer.plus, 3
+	case 4:
+		return lexer.minus, 3
+	case 5:
+		return lexer.divide, 4
+	}
+	src.ReportExpected(exprLexerInfixExpected...)
+	return nil, 0
+}
+
+type valueToken struct {
+	lexer *exprLexer
+}
+
+func (token *valueToken) PrefixParse(src *parse.Source) interface{} {
+	return token.lexer.actions.Value(src)
+}
+
+type groupToken struct {
+	lexer *exprLexer
+}
+
+func (token *groupToken) PrefixParse(src *parse.Source) interface{} {
+	src.ReadN(1) // "("
+	inner := token.lexer.Parse(src, 0)
+	if exprLexerFailed(src, inner) {
+		return nil
+	}
+	token.lexer.skip(src)
+	if !exprLexerMatch(src, ")") {
+		src.ReportExpected(`")"`)
+		src.ReportError(src.ExpectedError())
+		return nil
+	}
+	src.ReadN(1) // ")"
+	return inner
+}
+
+type plusToken struct {
+	lexer *exprLexer
+}
+
+func (token *plusToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.ReadN(1) // "+"
+	right := token.lexer.Parse(src, 3)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.InfixPlus(left, right)
+}
+
+type minusToken struct {
+	lexer *exprLexer
+}
+
+func (token *minusToken) PrefixParse(src *parse.Source) interface{} {
+	src.ReadN(1) // "-"
+	right := token.lexer.Parse(src, 6)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.PrefixMinus(right)
+}
+
+func (token *minusToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.ReadN(1) // "-"
+	right := token.lexer.Parse(src, 3)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.InfixMinus(left, right)
+}
+
+type multiplyToken struct {
+	lexer *exprLexer
+}
+
+func (token *multiplyToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.ReadN(1) // "*"
+	right := token.lexer.Parse(src, 4)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.InfixMultiply(left, right)
+}
+
+type divideToken struct {
+	lexer *exprLexer
+}
+
+func (token *divideToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.ReadN(1) // "/"
+	right := token.lexer.Parse(src, 4)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.InfixDivide(left, right)
+}
+
+type powerToken struct {
+	lexer *exprLexer
+}
+
+func (token *powerToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.ReadN(2) // "**"
+	right := token.lexer.Parse(src, 4)
+	if exprLexerFailed(src, right) {
+		return nil
+	}
+	return token.lexer.actions.InfixPower(left, right)
+}
+
+type bangToken struct {
+	lexer *exprLexer
+}
+
+func (token *bangToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.ReadN(1) // "!"
+	return token.lexer.actions.PostfixBang(left)
+}
@@ -0,0 +1,61 @@
+package calc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse/example/calc"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestEval(t *testing.T) {
+	t.Run("1＋1", test.Case(func(ctx context.Context) {
+		must.Equal(2, must.Call(calc.Eval, `1+1`)[0])
+	}))
+	t.Run("－1＋2", test.Case(func(ctx context.Context) {
+		must.Equal(1, must.Call(calc.Eval, `-1+2`)[0])
+	}))
+	t.Run("2×3＋1", test.Case(func(ctx context.Context) {
+		must.Equal(7, must.Call(calc.Eval, `2*3+1`)[0])
+	}))
+	t.Run("4/（1＋1）＋2", test.Case(func(ctx context.Context) {
+		must.Equal(4, must.Call(calc.Eval, `4 / (1 + 1) + 2`)[0])
+	}))
+	t.Run("2**3**2", test.Case(func(ctx context.Context) {
+		must.Equal(512, must.Call(calc.Eval, `2**3**2`)[0])
+	}))
+	t.Run("3!", test.Case(func(ctx context.Context) {
+		must.Equal(12, must.Call(calc.Eval, `2*3!`)[0])
+	}))
+	t.Run("group not closed", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`(1+1`)
+		must.Equal(`1:5: expected ")"; found EOF`, err.Error())
+	}))
+	t.Run("group closed by wrong bracket", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`(1+2]`)
+		must.Equal(`1:5: expected one of "!", "**", "*", "+", "-", "/", ")"; found "]"`, err.Error())
+	}))
+	t.Run("trailing input", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`1 2`)
+		must.Equal(`1:3: expected one of "!", "**", "*", "+", "-", "/"; found "2"`, err.Error())
+	}))
+	t.Run("truncated infix", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`1+`)
+		must.Equal(`1:3: expected one of "(", "-", value; found EOF`, err.Error())
+	}))
+	t.Run("truncated prefix", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`-`)
+		must.Equal(`1:2: expected one of "(", "-", value; found EOF`, err.Error())
+	}))
+	t.Run("truncated group", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`(1*`)
+		must.NotNil(err)
+	}))
+	t.Run("division by zero", test.Case(func(ctx context.Context) {
+		_, err := calc.Eval(`1/0`)
+		must.Equal(`division by zero`, err.Error())
+		_, err = calc.Eval(`1/(2-2)+1`)
+		must.Equal(`division by zero`, err.Error())
+	}))
+}
@@ -0,0 +1,97 @@
+package parse
+
+import (
+	"bytes"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SyntaxError tells what were expected at the position, and what was found
+type SyntaxError struct {
+	Position
+	Expected []string
+	Found    string
+}
+
+func (err *SyntaxError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString(err.Position.String())
+	switch len(err.Expected) {
+	case 0:
+		buf.WriteString(": unexpected ")
+		buf.WriteString(err.Found)
+		return buf.String()
+	case 1:
+		buf.WriteString(": expected ")
+	default:
+		buf.WriteString(": expected one of ")
+	}
+	for i, expected := range err.Expected {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(expected)
+	}
+	buf.WriteString("; found ")
+	buf.WriteString(err.Found)
+	return buf.String()
+}
+
+// ReportExpected record the labels expected at the cursor, like `number` or `"+"`.
+// Only the expectations at the farthest position are kept,
+// rollback to savepoint does not clear them.
+// Calling without labels just marks the cursor as the farthest position.
+// The ExpectN methods record the expected literals automatically.
+func (src *Source) ReportExpected(labels ...string) {
+	if src.nextIdx < src.expectedOffset {
+		return
+	}
+	if src.nextIdx > src.expectedOffset {
+		src.expectedOffset = src.nextIdx
+		src.expected = src.expected[:0]
+	}
+	for _, label := range labels {
+		src.addExpected(label)
+	}
+}
+
+func (src *Source) addExpected(label string) {
+	for _, expected := range src.expected {
+		if expected == label {
+			return
+		}
+	}
+	src.expected = append(src.expected, label)
+}
+
+func (src *Source) reportExpectedBytes(expected []byte) {
+	if src.nextIdx < src.expectedOffset {
+		return
+	}
+	src.ReportExpected(strconv.Quote(string(expected)))
+}
+
+// ExpectedError build the error from the expectations at the farthest position
+func (src *Source) ExpectedError() *SyntaxError {
+	offset := src.expectedOffset
+	if offset < src.nextIdx {
+		offset = src.nextIdx
+	}
+	err := &SyntaxError{
+		Position: src.positionAt(offset),
+		Found:    "EOF",
+	}
+	if offset == src.expectedOffset {
+		err.Expected = append([]string(nil), src.expected...)
+	}
+	for offset+utf8.UTFMax > len(src.readBytes) {
+		if src.fill() != nil {
+			break
+		}
+	}
+	if offset < len(src.readBytes) {
+		r, _ := utf8.DecodeRune(src.readBytes[offset:])
+		err.Found = strconv.Quote(string(r))
+	}
+	return err
+}
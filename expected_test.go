@@ -0,0 +1,58 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_ReportExpected(t *testing.T) {
+	t.Run("expected one of", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "(1+2]")[0].(*parse.Source)
+		src.ReadN(4)
+		src.StoreSavepoint()
+		must.Equal(false, src.Expect1(')'))
+		src.RollbackToSavepoint()
+		src.StoreSavepoint()
+		must.Equal(false, src.Expect1('+'))
+		src.RollbackToSavepoint()
+		src.ReportExpected(`"*"`, `"+"`)
+		must.Equal(`1:5: expected one of ")", "+", "*"; found "]"`, src.ExpectedError().Error())
+	}))
+	t.Run("farthest position wins", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		must.Equal(false, src.Expect2('x', 'y'))
+		src.Read1()
+		must.Equal(false, src.Expect([]byte("bd")))
+		must.Equal(`1:2: expected "bd"; found "b"`, src.ExpectedError().Error())
+	}))
+	t.Run("nearer position ignored", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		src.StoreSavepoint()
+		src.ReadN(2)
+		src.ReportExpected("number")
+		src.RollbackToSavepoint()
+		src.ReportExpected("identifier")
+		err := src.ExpectedError()
+		must.Equal(2, err.Offset)
+		must.Equal([]string{"number"}, err.Expected)
+		must.Equal(`"c"`, err.Found)
+	}))
+	t.Run("found EOF", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a")[0].(*parse.Source)
+		must.Equal(false, src.Expect2('a', 'b'))
+		must.Equal(`1:1: expected "ab"; found "a"`, src.ExpectedError().Error())
+		src.ResetError()
+		src.Read1()
+		src.ReportExpected("number")
+		must.Equal(`1:2: expected number; found EOF`, src.ExpectedError().Error())
+	}))
+	t.Run("unexpected", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "ab")[0].(*parse.Source)
+		src.Read1()
+		must.Equal(`1:2: unexpected "b"`, src.ExpectedError().Error())
+	}))
+}
@@ -0,0 +1,82 @@
+package parse
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExpectFold like Expect, but the ASCII letters are compared case-insensitively.
+// The matched bytes are returned as is, to preserve the casing, nil if not match.
+func (src *Source) ExpectFold(expect []byte) []byte {
+	if src.Error() != nil {
+		return nil
+	}
+	buf := src.PeekN(len(expect))
+	if len(buf) == len(expect) && equalFoldASCII(buf, expect) {
+		src.nextIdx += len(expect)
+		return buf
+	}
+	src.reportExpectedBytes(expect)
+	return nil
+}
+
+func equalFoldASCII(data []byte, expect []byte) bool {
+	for i, b := range data {
+		e := expect[i]
+		if b == e {
+			continue
+		}
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		if 'A' <= e && e <= 'Z' {
+			e += 'a' - 'A'
+		}
+		if b != e || b < 'a' || b > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ExpectFoldUnicode like ExpectFold, but the runes are compared by unicode simple folding,
+// so "straße" does not match "STRASSE", while the kelvin sign matches "k".
+// The matched bytes might be of different length from the expected.
+func (src *Source) ExpectFoldUnicode(expect []byte) []byte {
+	if src.Error() != nil {
+		return nil
+	}
+	src.StoreSavepoint()
+	start := src.nextIdx
+	for rest := expect; len(rest) > 0; {
+		e, size := utf8.DecodeRune(rest)
+		r, n := src.PeekRune()
+		if n == 0 || !equalFoldRune(r, e) {
+			err := src.Error()
+			src.RollbackToSavepoint()
+			src.reportExpectedBytes(expect)
+			if err != nil {
+				src.ReportError(err)
+			}
+			return nil
+		}
+		src.nextIdx += n
+		rest = rest[size:]
+	}
+	end := src.nextIdx
+	src.RollbackToSavepoint()
+	return src.ReadN(end - start)
+}
+
+// equalFoldRune tells if the runes are in the same simple folding orbit
+func equalFoldRune(r, e rune) bool {
+	if r == e {
+		return true
+	}
+	for f := unicode.SimpleFold(e); f != e; f = unicode.SimpleFold(f) {
+		if f == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package parse_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_ExpectFold(t *testing.T) {
+	t.Run("preserve casing", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "SeLeCt *")[0].(*parse.Source)
+		must.Equal("SeLeCt", string(src.ExpectFold([]byte("select"))))
+		must.Equal(byte(' '), src.Peek1())
+	}))
+	t.Run("not match", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "Content-Type")[0].(*parse.Source)
+		must.Nil(src.ExpectFold([]byte("content_type")))
+		must.Nil(src.Error())
+		must.Equal(`1:1: expected "content_type"; found "C"`, src.ExpectedError().Error())
+	}))
+	t.Run("symbols are not folded", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "[")[0].(*parse.Source)
+		// '[' is 'Z'+1 and '{' is 'z'+1
+		must.Nil(src.ExpectFold([]byte("{")))
+	}))
+	t.Run("EOF", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "FRO")[0].(*parse.Source)
+		must.Nil(src.ExpectFold([]byte("from")))
+		must.Equal(io.ErrUnexpectedEOF, src.Error())
+	}))
+}
+
+func TestSource_ExpectFoldUnicode(t *testing.T) {
+	t.Run("preserve casing", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\u0394\u0395\u039b\u03a4\u0391!")[0].(*parse.Source)
+		must.Equal("\u0394\u0395\u039b\u03a4\u0391", string(src.ExpectFoldUnicode([]byte("\u03b4\u03b5\u03bb\u03c4\u03b1"))))
+		must.Equal(byte('!'), src.Peek1())
+	}))
+	t.Run("different length", test.Case(func(ctx context.Context) {
+		// the kelvin sign is 3 bytes
+		src := must.Call(parse.NewSourceString, "\u212aey")[0].(*parse.Source)
+		must.Equal("\u212aey", string(src.ExpectFoldUnicode([]byte("KEY"))))
+	}))
+	t.Run("not match", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "stra\u00dfe")[0].(*parse.Source)
+		must.Nil(src.ExpectFoldUnicode([]byte("STRASSE")))
+		must.Nil(src.Error())
+		must.Equal(0, src.Offset())
+		must.Equal(`1:1: expected "STRASSE"; found "s"`, src.ExpectedError().Error())
+	}))
+	t.Run("EOF", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\u00c9t\u00c9")[0].(*parse.Source)
+		must.Nil(src.ExpectFoldUnicode([]byte("\u00e9t\u00e9s")))
+		must.Equal(io.ErrUnexpectedEOF, src.Error())
+		must.Equal(0, src.Offset())
+	}))
+}
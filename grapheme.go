@@ -0,0 +1,244 @@
+package parse
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// PeekGrapheme read one extended grapheme cluster of UAX #29, without moving cursor.
+// The invalid byte is a cluster by itself, handled by the policy like PeekUtf8.
+// The conjunct rule GB9c of Indic scripts is not implemented.
+func (src *Source) PeekGrapheme() []byte {
+	r, n := src.PeekRune()
+	if n == 0 {
+		return nil
+	}
+	if r == utf8.RuneError && n == 1 {
+		return src.PeekUtf8()
+	}
+	for window := 16; ; window *= 2 {
+		data := src.peekUpTo(window)
+		length := graphemeLen(data)
+		// the rune after the cluster must be complete to decide the break
+		if length+utf8.UTFMax <= len(data) || len(data) < window {
+			return data[:length]
+		}
+	}
+}
+
+// graphemeLen returns the length of first grapheme cluster in data
+func graphemeLen(data []byte) int {
+	var breaker graphemeBreaker
+	length := 0
+	for length < len(data) {
+		r, n := utf8.DecodeRune(data[length:])
+		invalid := r == utf8.RuneError && n == 1
+		if length > 0 && invalid {
+			break
+		}
+		if breaker.breakBefore(r) && length > 0 {
+			break
+		}
+		length += n
+		if invalid {
+			break
+		}
+	}
+	return length
+}
+
+// graphemeProperty is the Grapheme_Cluster_Break property
+type graphemeProperty int
+
+const (
+	gbNone graphemeProperty = iota
+	gbOther
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+	gbExtendedPictographic
+)
+
+// graphemeBreaker tracks the runes seen, to tell the boundaries
+type graphemeBreaker struct {
+	prev graphemeProperty
+	// pictographic is true after Extended_Pictographic Extend*
+	pictographic bool
+	// zwj is true after Extended_Pictographic Extend* ZWJ
+	zwj bool
+	// regionalIndicators counts the consecutive regional indicators
+	regionalIndicators int
+}
+
+// breakBefore tells if there is a boundary before the rune, then the rune is accepted
+func (breaker *graphemeBreaker) breakBefore(r rune) bool {
+	prop := graphemePropertyOf(r)
+	brk := breaker.breaks(prop)
+	breaker.zwj = prop == gbZWJ && breaker.pictographic
+	breaker.pictographic = prop == gbExtendedPictographic || prop == gbExtend && breaker.pictographic
+	if prop == gbRegionalIndicator {
+		breaker.regionalIndicators++
+	} else {
+		breaker.regionalIndicators = 0
+	}
+	breaker.prev = prop
+	return brk
+}
+
+func (breaker *graphemeBreaker) breaks(prop graphemeProperty) bool {
+	prev := breaker.prev
+	switch {
+	case prev == gbNone:
+		return true
+	case prev == gbCR && prop == gbLF:
+		return false
+	case prev == gbControl || prev == gbCR || prev == gbLF:
+		return true
+	case prop == gbControl || prop == gbCR || prop == gbLF:
+		return true
+	case prev == gbL && (prop == gbL || prop == gbV || prop == gbLV || prop == gbLVT):
+		return false
+	case (prev == gbLV || prev == gbV) && (prop == gbV || prop == gbT):
+		return false
+	case (prev == gbLVT || prev == gbT) && prop == gbT:
+		return false
+	case prop == gbExtend || prop == gbZWJ || prop == gbSpacingMark:
+		return false
+	case prev == gbPrepend:
+		return false
+	case breaker.zwj && prop == gbExtendedPictographic:
+		return false
+	case prop == gbRegionalIndicator && breaker.regionalIndicators%2 == 1:
+		return false
+	}
+	return true
+}
+
+func graphemePropertyOf(r rune) graphemeProperty {
+	if r < utf8.RuneSelf {
+		switch {
+		case r == '\r':
+			return gbCR
+		case r == '\n':
+			return gbLF
+		case r < 0x20 || r == 0x7F:
+			return gbControl
+		}
+		return gbOther
+	}
+	switch {
+	case r == 0x200D:
+		return gbZWJ
+	case r == 0x200C || 0x1F3FB <= r && r <= 0x1F3FF:
+		// zero width non-joiner and emoji modifiers
+		return gbExtend
+	case 0x1F1E6 <= r && r <= 0x1F1FF:
+		return gbRegionalIndicator
+	case 0x1100 <= r && r <= 0x115F || 0xA960 <= r && r <= 0xA97C:
+		return gbL
+	case 0x1160 <= r && r <= 0x11A7 || 0xD7B0 <= r && r <= 0xD7C6:
+		return gbV
+	case 0x11A8 <= r && r <= 0x11FF || 0xD7CB <= r && r <= 0xD7FB:
+		return gbT
+	case 0xAC00 <= r && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.Is(unicode.Prepended_Concatenation_Mark, r):
+		return gbPrepend
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r) || r == 0x0E33 || r == 0x0EB3:
+		return gbSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case unicode.Is(extendedPictographic, r):
+		return gbExtendedPictographic
+	}
+	return gbOther
+}
+
+// extendedPictographic is the Extended_Pictographic property of emoji-data.txt
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00A9, Hi: 0x00AE, Stride: 5},
+		{Lo: 0x203C, Hi: 0x2049, Stride: 13},
+		{Lo: 0x2122, Hi: 0x2139, Stride: 23},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21A9, Hi: 0x21AA, Stride: 1},
+		{Lo: 0x231A, Hi: 0x231B, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2388, Stride: 96},
+		{Lo: 0x23CF, Hi: 0x23CF, Stride: 1},
+		{Lo: 0x23E9, Hi: 0x23F3, Stride: 1},
+		{Lo: 0x23F8, Hi: 0x23FA, Stride: 1},
+		{Lo: 0x24C2, Hi: 0x24C2, Stride: 1},
+		{Lo: 0x25AA, Hi: 0x25AB, Stride: 1},
+		{Lo: 0x25B6, Hi: 0x25C0, Stride: 10},
+		{Lo: 0x25FB, Hi: 0x25FE, Stride: 1},
+		{Lo: 0x2600, Hi: 0x2605, Stride: 1},
+		{Lo: 0x2607, Hi: 0x2612, Stride: 1},
+		{Lo: 0x2614, Hi: 0x2685, Stride: 1},
+		{Lo: 0x2690, Hi: 0x2705, Stride: 1},
+		{Lo: 0x2708, Hi: 0x2712, Stride: 1},
+		{Lo: 0x2714, Hi: 0x2716, Stride: 2},
+		{Lo: 0x271D, Hi: 0x2721, Stride: 4},
+		{Lo: 0x2728, Hi: 0x2728, Stride: 1},
+		{Lo: 0x2733, Hi: 0x2734, Stride: 1},
+		{Lo: 0x2744, Hi: 0x2747, Stride: 3},
+		{Lo: 0x274C, Hi: 0x274E, Stride: 2},
+		{Lo: 0x2753, Hi: 0x2755, Stride: 1},
+		{Lo: 0x2757, Hi: 0x2757, Stride: 1},
+		{Lo: 0x2763, Hi: 0x2767, Stride: 1},
+		{Lo: 0x2795, Hi: 0x2797, Stride: 1},
+		{Lo: 0x27A1, Hi: 0x27B0, Stride: 15},
+		{Lo: 0x27BF, Hi: 0x27BF, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2B05, Hi: 0x2B07, Stride: 1},
+		{Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1},
+		{Lo: 0x2B50, Hi: 0x2B55, Stride: 5},
+		{Lo: 0x3030, Hi: 0x303D, Stride: 13},
+		{Lo: 0x3297, Hi: 0x3299, Stride: 2},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1F000, Hi: 0x1F0FF, Stride: 1},
+		{Lo: 0x1F10D, Hi: 0x1F10F, Stride: 1},
+		{Lo: 0x1F12F, Hi: 0x1F12F, Stride: 1},
+		{Lo: 0x1F16C, Hi: 0x1F171, Stride: 1},
+		{Lo: 0x1F17E, Hi: 0x1F17F, Stride: 1},
+		{Lo: 0x1F18E, Hi: 0x1F18E, Stride: 1},
+		{Lo: 0x1F191, Hi: 0x1F19A, Stride: 1},
+		{Lo: 0x1F1AD, Hi: 0x1F1E5, Stride: 1},
+		{Lo: 0x1F201, Hi: 0x1F20F, Stride: 1},
+		{Lo: 0x1F21A, Hi: 0x1F21A, Stride: 1},
+		{Lo: 0x1F22F, Hi: 0x1F22F, Stride: 1},
+		{Lo: 0x1F232, Hi: 0x1F23A, Stride: 1},
+		{Lo: 0x1F23C, Hi: 0x1F23F, Stride: 1},
+		{Lo: 0x1F249, Hi: 0x1F3FA, Stride: 1},
+		{Lo: 0x1F400, Hi: 0x1F53D, Stride: 1},
+		{Lo: 0x1F546, Hi: 0x1F64F, Stride: 1},
+		{Lo: 0x1F680, Hi: 0x1F6FF, Stride: 1},
+		{Lo: 0x1F774, Hi: 0x1F77F, Stride: 1},
+		{Lo: 0x1F7D5, Hi: 0x1F7FF, Stride: 1},
+		{Lo: 0x1F80C, Hi: 0x1F80F, Stride: 1},
+		{Lo: 0x1F848, Hi: 0x1F84F, Stride: 1},
+		{Lo: 0x1F85A, Hi: 0x1F85F, Stride: 1},
+		{Lo: 0x1F888, Hi: 0x1F88F, Stride: 1},
+		{Lo: 0x1F8AE, Hi: 0x1F8FF, Stride: 1},
+		{Lo: 0x1F90C, Hi: 0x1F93A, Stride: 1},
+		{Lo: 0x1F93C, Hi: 0x1F945, Stride: 1},
+		{Lo: 0x1F947, Hi: 0x1FAFF, Stride: 1},
+		{Lo: 0x1FC00, Hi: 0x1FFFD, Stride: 1},
+	},
+	LatinOffset: 1,
+}
@@ -0,0 +1,142 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// IdentifierOptions configure the identifier syntax.
+// By default, it is the default identifier of UAX #31:
+// starts with XID_Start or '_', continues with XID_Continue.
+type IdentifierOptions struct {
+	// Dollar allows '$' anywhere in identifier, like JavaScript
+	Dollar bool
+	// Hyphen allows '-' after the first rune, like CSS and Lisp
+	Hyphen bool
+	// NFC reports error if the identifier is not in Normalization Form C
+	NFC bool
+	// SafeScript reports *SpoofingError if the identifier mixes scripts,
+	// or it looks like Latin but not, following the highly restrictive profile of UTS #39
+	SafeScript bool
+	// Keywords is the reserved words, the hit is reported
+	Keywords map[string]bool
+}
+
+var errIdentifierNotFound = errors.New("identifier not found")
+
+// SkipIdentifier skip the identifier defined by options, nil options means UAX #31 default.
+// It returns how many bytes skipped, and whether the identifier is one of the keywords.
+// If not starting with identifier, "identifier" is reported as expected.
+func (src *Source) SkipIdentifier(opts *IdentifierOptions) (int, bool) {
+	if opts == nil {
+		opts = &IdentifierOptions{}
+	}
+	start := src.nextIdx
+	for src.Error() == nil {
+		r, n := src.PeekRune()
+		if src.Error() != nil {
+			break
+		}
+		if src.nextIdx == start && !opts.IsStart(r) || src.nextIdx > start && !opts.IsContinue(r) {
+			break
+		}
+		src.ReadN(n)
+	}
+	if err := src.Error(); err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, false
+	}
+	src.err = nil
+	ident := src.readBytes[start:src.nextIdx]
+	src.nextIdx = start
+	if len(ident) == 0 {
+		src.ReportExpected("identifier")
+		src.ReportError(errIdentifierNotFound)
+		return 0, false
+	}
+	if opts.NFC && !norm.NFC.IsNormal(ident) {
+		src.ReportError(fmt.Errorf("identifier %q is not in NFC", ident))
+		return 0, false
+	}
+	if opts.SafeScript {
+		if reason := checkScripts(string(ident)); reason != "" {
+			src.ReportError(&SpoofingError{
+				Position: src.Position(),
+				Message:  fmt.Sprintf("identifier %q %s", ident, reason),
+			})
+			return 0, false
+		}
+	}
+	src.nextIdx += len(ident)
+	return len(ident), opts.Keywords[string(ident)]
+}
+
+// IsStart tells if the rune can start the identifier
+func (opts *IdentifierOptions) IsStart(r rune) bool {
+	if r == '_' || opts.Dollar && r == '$' {
+		return true
+	}
+	return IsXIDStart(r)
+}
+
+// IsContinue tells if the rune can continue the identifier
+func (opts *IdentifierOptions) IsContinue(r rune) bool {
+	if opts.Dollar && r == '$' || opts.Hyphen && r == '-' {
+		return true
+	}
+	return IsXIDContinue(r)
+}
+
+// IsXIDStart tells if the rune has XID_Start property
+func IsXIDStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	if unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space) {
+		return false
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) &&
+		!unicode.Is(notXIDStart, r)
+}
+
+// IsXIDContinue tells if the rune has XID_Continue property
+func IsXIDContinue(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_'
+	}
+	if unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space) {
+		return false
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start,
+		unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!unicode.Is(notXIDContinue, r)
+}
+
+// notXIDStart is ID_Start but not XID_Start, they are modified by NFKC
+var notXIDStart = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x037A, Hi: 0x037A, Stride: 1},
+		{Lo: 0x0E33, Hi: 0x0E33, Stride: 1},
+		{Lo: 0x0EB3, Hi: 0x0EB3, Stride: 1},
+		{Lo: 0x309B, Hi: 0x309C, Stride: 1},
+		{Lo: 0xFC5E, Hi: 0xFC63, Stride: 1},
+		{Lo: 0xFDFA, Hi: 0xFDFB, Stride: 1},
+		{Lo: 0xFE70, Hi: 0xFE7E, Stride: 2},
+		{Lo: 0xFF9E, Hi: 0xFF9F, Stride: 1},
+	},
+}
+
+// notXIDContinue is ID_Continue but not XID_Continue, they are modified by NFKC
+var notXIDContinue = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x037A, Hi: 0x037A, Stride: 1},
+		{Lo: 0x309B, Hi: 0x309C, Stride: 1},
+		{Lo: 0xFC5E, Hi: 0xFC63, Stride: 1},
+		{Lo: 0xFDFA, Hi: 0xFDFB, Stride: 1},
+		{Lo: 0xFE70, Hi: 0xFE7E, Stride: 2},
+	},
+}
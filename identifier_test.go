@@ -0,0 +1,27 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_SkipIdentifier(t *testing.T) {
+	t.Run("keyword at eof", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "func")[0].(*parse.Source)
+		length, keyword := src.SkipIdentifier(&parse.IdentifierOptions{Keywords: map[string]bool{"func": true}})
+		must.Equal(4, length)
+		must.Equal(true, keyword)
+		must.Nil(src.Error())
+	}))
+	t.Run("mixed scripts", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "p\u0430ypal")[0].(*parse.Source)
+		length, _ := src.SkipIdentifier(&parse.IdentifierOptions{SafeScript: true})
+		must.Equal(0, length)
+		must.Equal(0, src.Offset())
+		must.NotNil(src.Error())
+	}))
+}
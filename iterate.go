@@ -0,0 +1,94 @@
+package parse
+
+import "strconv"
+
+// IterateOptions configure how the top level items are separated
+type IterateOptions struct {
+	// Separators are the bytes between items, like '\n' or ';'.
+	// Consecutive separators are skipped, so empty items are ignored.
+	// If empty, items follow each other directly, like concatenated JSON values.
+	Separators []byte
+}
+
+// Iterator parse the top level items one by one,
+// like records of NDJSON or statements of script.
+type Iterator struct {
+	src        *Source
+	lexer      Lexer
+	separators [256]bool
+	expected   []string
+	value      interface{}
+	position   Position
+	err        error
+	done       bool
+}
+
+// Iterate parse the source as sequence of top level items.
+// The bytes of parsed items are discarded, so the memory is bounded by the longest item.
+func Iterate(src *Source, lexer Lexer, opts IterateOptions) *Iterator {
+	iterator := &Iterator{src: src, lexer: lexer}
+	for _, b := range opts.Separators {
+		if !iterator.separators[b] {
+			iterator.separators[b] = true
+			iterator.expected = append(iterator.expected, strconv.Quote(string(b)))
+		}
+	}
+	return iterator
+}
+
+// Next parse the next item,
+// returns false if no more item or error happened.
+func (iterator *Iterator) Next() bool {
+	if iterator.done {
+		return false
+	}
+	src := iterator.src
+	iterator.value = nil
+	src.discardRead()
+	for {
+		b := src.Peek1()
+		if src.Error() != nil || !iterator.separators[b] {
+			break
+		}
+		src.Read1()
+	}
+	if src.Error() != nil {
+		// no more item
+		return iterator.stop(src.parseError())
+	}
+	iterator.position = src.Position()
+	value := Parse(src, iterator.lexer, 0)
+	if err := src.parseError(); err != nil {
+		return iterator.stop(err)
+	}
+	if src.Error() == nil && len(iterator.expected) > 0 {
+		b := src.Peek1()
+		if src.Error() == nil && !iterator.separators[b] {
+			src.ReportExpected(iterator.expected...)
+			return iterator.stop(src.ExpectedError())
+		}
+	}
+	iterator.value = value
+	return true
+}
+
+func (iterator *Iterator) stop(err error) bool {
+	iterator.done = true
+	iterator.err = err
+	return false
+}
+
+// Value returns the item parsed by Next
+func (iterator *Iterator) Value() interface{} {
+	return iterator.value
+}
+
+// Position returns where the item parsed by Next starts
+func (iterator *Iterator) Position() Position {
+	return iterator.position
+}
+
+// Err returns the error stopped the iteration, nil if all items parsed
+func (iterator *Iterator) Err() error {
+	return iterator.err
+}
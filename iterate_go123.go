@@ -0,0 +1,21 @@
+//go:build go1.23
+// +build go1.23
+
+package parse
+
+import "iter"
+
+// All returns the items as iter.Seq2,
+// the error stopped the iteration is yielded last with nil value.
+func (iterator *Iterator) All() iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		for iterator.Next() {
+			if !yield(iterator.Value(), nil) {
+				return
+			}
+		}
+		if iterator.Err() != nil {
+			yield(nil, iterator.Err())
+		}
+	}
+}
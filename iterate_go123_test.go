@@ -0,0 +1,30 @@
+//go:build go1.23
+// +build go1.23
+
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestIterator_All(t *testing.T) {
+	t.Run("values then error", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "1\n2+2\n+")[0].(*parse.Source)
+		var values []interface{}
+		var errs []string
+		for value, err := range parse.Iterate(src, &sumLexer{}, parse.IterateOptions{Separators: []byte{'\n'}}).All() {
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			values = append(values, value)
+		}
+		must.Equal([]interface{}{1, 4}, values)
+		must.Equal([]string{`3:1: expected number; found "+"`}, errs)
+	}))
+}
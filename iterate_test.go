@@ -0,0 +1,118 @@
+package parse_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+// sumLexer parse expression like 1 + 2 + 3
+type sumLexer struct {
+}
+
+func (lexer *sumLexer) skip(src *parse.Source) {
+	for src.Error() == nil && src.Peek1() == ' ' {
+		src.Read1()
+	}
+}
+
+func (lexer *sumLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	lexer.skip(src)
+	b := src.Peek1()
+	if src.Error() == nil && b >= '0' && b <= '9' {
+		return &numberToken{}
+	}
+	src.ReportExpected("number")
+	return nil
+}
+
+func (lexer *sumLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	lexer.skip(src)
+	if src.Peek1() == '+' && src.Error() == nil {
+		return &plusToken{lexer: lexer}, parse.DefaultPrecedence
+	}
+	src.ReportExpected(`"+"`)
+	return nil, 0
+}
+
+type numberToken struct {
+}
+
+func (token *numberToken) PrefixParse(src *parse.Source) interface{} {
+	value := 0
+	for {
+		b := src.Peek1()
+		if src.Error() != nil || b < '0' || b > '9' {
+			return value
+		}
+		src.Read1()
+		value = value*10 + int(b-'0')
+	}
+}
+
+type plusToken struct {
+	lexer *sumLexer
+}
+
+func (token *plusToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.Read1()
+	right := parse.Parse(src, token.lexer, parse.DefaultPrecedence)
+	if right == nil {
+		return nil
+	}
+	return left.(int) + right.(int)
+}
+
+func iterate(input string, separators string) ([]interface{}, error) {
+	src, err := parse.NewSource(strings.NewReader(input), 4)
+	if err != nil {
+		return nil, err
+	}
+	iterator := parse.Iterate(src, &sumLexer{}, parse.IterateOptions{Separators: []byte(separators)})
+	var values []interface{}
+	for iterator.Next() {
+		values = append(values, iterator.Value())
+	}
+	return values, iterator.Err()
+}
+
+func TestIterate(t *testing.T) {
+	t.Run("newline separated", test.Case(func(ctx context.Context) {
+		values := must.Call(iterate, "1+2\n3\n\n4 + 5 + 6\n", "\n")[0]
+		must.Equal([]interface{}{3, 3, 15}, values)
+	}))
+	t.Run("multiple separators", test.Case(func(ctx context.Context) {
+		values := must.Call(iterate, "1;2+2\n;3", ";\n")[0]
+		must.Equal([]interface{}{1, 4, 3}, values)
+	}))
+	t.Run("no separator", test.Case(func(ctx context.Context) {
+		values := must.Call(iterate, "1 2+3 4", "")[0]
+		must.Equal([]interface{}{1, 5, 4}, values)
+	}))
+	t.Run("separator missing", test.Case(func(ctx context.Context) {
+		values, err := iterate("1;1 2", ";")
+		must.Equal([]interface{}{1}, values)
+		must.Equal(`1:5: expected one of "+", ";"; found "2"`, err.Error())
+	}))
+	t.Run("syntax error after discarded items", test.Case(func(ctx context.Context) {
+		values, err := iterate("1\n22+3\n4+\n", "\n")
+		must.Equal([]interface{}{1, 25}, values)
+		must.Equal(`3:3: expected number; found "\n"`, err.Error())
+	}))
+	t.Run("offset counts discarded bytes", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "12;345;6")[0].(*parse.Source)
+		iterator := parse.Iterate(src, &sumLexer{}, parse.IterateOptions{Separators: []byte{';'}})
+		must.Equal(true, iterator.Next())
+		must.Equal(true, iterator.Next())
+		must.Equal(345, iterator.Value())
+		must.Equal(6, src.Offset())
+		must.Equal("1:7", src.Position().String())
+		must.Equal(true, iterator.Next())
+		must.Equal(false, iterator.Next())
+		must.Nil(iterator.Err())
+	}))
+}
@@ -0,0 +1,304 @@
+// Package layout supports the offside rule, like Python and YAML.
+// The layout tracks the indentation of lines,
+// and tells the lexer the virtual NEWLINE, INDENT and DEDENT tokens at the cursor.
+// The lexer should ask Peek before dispatching on the real tokens,
+// and Consume the virtual token when it is parsed.
+package layout
+
+import (
+	"fmt"
+
+	"github.com/modern-go/parse"
+)
+
+// Kind is the kind of virtual token
+type Kind int
+
+const (
+	// None means the next token is a real one
+	None Kind = iota
+	// Newline ends a logical line
+	Newline
+	// Indent starts a block
+	Indent
+	// Dedent ends a block
+	Dedent
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case Newline:
+		return "NEWLINE"
+	case Indent:
+		return "INDENT"
+	case Dedent:
+		return "DEDENT"
+	}
+	return "NONE"
+}
+
+// Options configure how to measure the indentation
+type Options struct {
+	// TabWidth is the column of tab stops, default to 8
+	TabWidth int
+	// Comment starts the comment to the end of line, like "#".
+	// The lines only having comment are blank lines.
+	Comment string
+}
+
+type level struct {
+	column int
+	// altColumn counts tab as 1 column, to detect inconsistent use of tabs and spaces
+	altColumn int
+}
+
+// Layout tracks the indentation levels and brackets.
+// The parser backtracking across line start should use the savepoint methods of layout,
+// instead of the ones of source, so the indentation state is restored together with the cursor.
+type Layout struct {
+	tabWidth int
+	comment  []byte
+	state
+	savepoints []state
+}
+
+// state is what changes while reading, saved at savepoint
+type state struct {
+	levels      []level
+	pending     []Kind
+	brackets    int
+	atLineStart bool
+	done        bool
+}
+
+// New create the layout for one source
+func New(opts Options) *Layout {
+	if opts.TabWidth <= 0 {
+		opts.TabWidth = 8
+	}
+	return &Layout{
+		tabWidth: opts.TabWidth,
+		comment:  []byte(opts.Comment),
+		state: state{
+			levels:      []level{{}},
+			atLineStart: true,
+		},
+	}
+}
+
+// StoreSavepoint store the savepoint of source, and the indentation state
+func (layout *Layout) StoreSavepoint(src *parse.Source) {
+	saved := layout.state
+	// the slices are modified in place later
+	saved.levels = append([]level(nil), layout.levels...)
+	saved.pending = append([]Kind(nil), layout.pending...)
+	layout.savepoints = append(layout.savepoints, saved)
+	src.StoreSavepoint()
+}
+
+// RollbackToSavepoint rollback the source and the indentation state to the savepoint
+func (layout *Layout) RollbackToSavepoint(src *parse.Source) {
+	if len(layout.savepoints) > 0 {
+		layout.state = layout.savepoints[len(layout.savepoints)-1]
+		layout.savepoints = layout.savepoints[:len(layout.savepoints)-1]
+	}
+	src.RollbackToSavepoint()
+}
+
+// DeleteSavepoint delete the savepoint of source and the indentation state
+func (layout *Layout) DeleteSavepoint(src *parse.Source) {
+	if len(layout.savepoints) > 0 {
+		layout.savepoints = layout.savepoints[:len(layout.savepoints)-1]
+	}
+	src.DeleteSavepoint()
+}
+
+// Open tells the layout a bracket is opened, newlines inside brackets are joined
+func (layout *Layout) Open() {
+	layout.brackets++
+}
+
+// Close tells the layout a bracket is closed
+func (layout *Layout) Close() {
+	if layout.brackets > 0 {
+		layout.brackets--
+	}
+}
+
+// Peek skips the spaces, comments and blank lines,
+// then tells the virtual token at the cursor, None if the next token is real.
+// It can be called repeatedly, the virtual token is not consumed.
+// At the end of input, NEWLINE and DEDENT for every open block are reported before None.
+func (layout *Layout) Peek(src *parse.Source) Kind {
+	if len(layout.pending) > 0 {
+		return layout.pending[0]
+	}
+	if src.Error() != nil || layout.done {
+		return None
+	}
+	if layout.atLineStart && layout.brackets == 0 {
+		layout.indent(src)
+		if len(layout.pending) > 0 || src.Error() != nil {
+			return layout.Peek(src)
+		}
+	}
+	layout.skip(src)
+	if src.Error() != nil {
+		return None
+	}
+	if atEOF(src) {
+		if !layout.atLineStart {
+			layout.pending = append(layout.pending, Newline)
+		}
+		layout.closeBlocks()
+		return layout.Peek(src)
+	}
+	if src.Peek1() == '\n' {
+		src.Read1()
+		layout.pending = append(layout.pending, Newline)
+		layout.atLineStart = true
+		return Newline
+	}
+	return None
+}
+
+// Consume the virtual token at the cursor, None if there is no virtual token
+func (layout *Layout) Consume() Kind {
+	if len(layout.pending) == 0 {
+		return None
+	}
+	kind := layout.pending[0]
+	layout.pending = layout.pending[1:]
+	return kind
+}
+
+// skip the spaces and comment to the end of line, newlines are also skipped inside brackets
+func (layout *Layout) skip(src *parse.Source) {
+	for !atEOF(src) {
+		switch src.Peek1() {
+		case ' ', '\t', '\r', '\f':
+			src.Read1()
+		case '\n':
+			if layout.brackets == 0 {
+				return
+			}
+			src.Read1()
+		default:
+			if !layout.skipComment(src) {
+				return
+			}
+		}
+	}
+}
+
+// skipComment skips the comment to the end of line, the newline is not consumed
+func (layout *Layout) skipComment(src *parse.Source) bool {
+	if len(layout.comment) == 0 || !startsWith(src, layout.comment) {
+		return false
+	}
+	for !atEOF(src) && src.Peek1() != '\n' {
+		src.Read1()
+	}
+	return true
+}
+
+// indent measures the indentation of next non blank line, compare it with the levels
+func (layout *Layout) indent(src *parse.Source) {
+	for {
+		column, altColumn := 0, 0
+		for !atEOF(src) {
+			b := src.Peek1()
+			if b == ' ' {
+				column++
+				altColumn++
+			} else if b == '\t' {
+				column = (column/layout.tabWidth + 1) * layout.tabWidth
+				altColumn++
+			} else if b == '\f' {
+				column, altColumn = 0, 0
+			} else {
+				break
+			}
+			src.Read1()
+		}
+		if atEOF(src) {
+			layout.closeBlocks()
+			return
+		}
+		layout.skipComment(src)
+		if atEOF(src) || src.Peek1() == '\r' || src.Peek1() == '\n' {
+			layout.skipLine(src)
+			continue
+		}
+		layout.atLineStart = false
+		layout.compare(src, level{column: column, altColumn: altColumn})
+		return
+	}
+}
+
+// skipLine skips the blank line including the newline
+func (layout *Layout) skipLine(src *parse.Source) {
+	for !atEOF(src) {
+		if src.Read1() == '\n' {
+			return
+		}
+	}
+}
+
+// closeBlocks dedent all the blocks at the end of input
+func (layout *Layout) closeBlocks() {
+	for len(layout.levels) > 1 {
+		layout.levels = layout.levels[:len(layout.levels)-1]
+		layout.pending = append(layout.pending, Dedent)
+	}
+	layout.done = true
+}
+
+func (layout *Layout) compare(src *parse.Source, current level) {
+	top := layout.levels[len(layout.levels)-1]
+	if current.column > top.column {
+		if current.altColumn <= top.altColumn {
+			layout.reportInconsistent(src)
+			return
+		}
+		layout.levels = append(layout.levels, current)
+		layout.pending = append(layout.pending, Indent)
+		return
+	}
+	for current.column < top.column {
+		layout.levels = layout.levels[:len(layout.levels)-1]
+		layout.pending = append(layout.pending, Dedent)
+		top = layout.levels[len(layout.levels)-1]
+	}
+	if current.column != top.column {
+		src.ReportError(fmt.Errorf("%v: unindent does not match any outer indentation level", src.Position()))
+		return
+	}
+	if current.altColumn != top.altColumn {
+		layout.reportInconsistent(src)
+	}
+}
+
+func (layout *Layout) reportInconsistent(src *parse.Source) {
+	src.ReportError(fmt.Errorf("%v: inconsistent use of tabs and spaces in indentation", src.Position()))
+}
+
+// atEOF tells if all input has been consumed, without leaving the source in error condition
+func atEOF(src *parse.Source) bool {
+	if src.Error() != nil {
+		return true
+	}
+	src.StoreSavepoint()
+	src.Peek1()
+	eof := src.Error() != nil
+	src.RollbackToSavepoint()
+	return eof
+}
+
+// startsWith tells if the source starts with the prefix, the cursor is not moved
+func startsWith(src *parse.Source, prefix []byte) bool {
+	src.StoreSavepoint()
+	matched := string(src.PeekN(len(prefix))) == string(prefix)
+	src.RollbackToSavepoint()
+	return matched
+}
@@ -0,0 +1,117 @@
+package layout_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/layout"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+// tokens list the words and virtual tokens, brackets are joining lines
+func tokens(input string, opts layout.Options) ([]string, error) {
+	src, err := parse.NewSourceString(input)
+	if err != nil {
+		return nil, err
+	}
+	lines := layout.New(opts)
+	var tokens []string
+	for {
+		if kind := lines.Peek(src); kind != layout.None {
+			tokens = append(tokens, lines.Consume().String())
+			continue
+		}
+		if src.Error() != nil {
+			return tokens, src.Error()
+		}
+		word := ""
+		for {
+			src.StoreSavepoint()
+			b := src.Peek1()
+			if src.Error() != nil || strings.IndexByte(" \t\n#", b) != -1 {
+				src.RollbackToSavepoint()
+				break
+			}
+			src.DeleteSavepoint()
+			src.Read1()
+			switch b {
+			case '(':
+				lines.Open()
+			case ')':
+				lines.Close()
+			}
+			word += string(b)
+		}
+		if word == "" {
+			return tokens, nil
+		}
+		tokens = append(tokens, word)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	t.Run("indent and dedent", test.Case(func(ctx context.Context) {
+		must.Equal(strings.Fields(`
+			a NEWLINE INDENT
+			b NEWLINE
+			c NEWLINE INDENT
+			d NEWLINE DEDENT DEDENT
+			e NEWLINE`),
+			must.Call(tokens, "a\n  b\n\n  c\n    d\ne", layout.Options{})[0])
+	}))
+	t.Run("blank and comment lines", test.Case(func(ctx context.Context) {
+		must.Equal(strings.Fields(`
+			a NEWLINE INDENT
+			b NEWLINE DEDENT`),
+			must.Call(tokens, "# head\na # tail\n   \n    # comment\n  b\n", layout.Options{Comment: "#"})[0])
+	}))
+	t.Run("implicit line joining", test.Case(func(ctx context.Context) {
+		must.Equal(strings.Fields(`
+			f( x y) NEWLINE
+			g NEWLINE`),
+			must.Call(tokens, "f(\n  x\ny)\ng", layout.Options{})[0])
+	}))
+	t.Run("tab width", test.Case(func(ctx context.Context) {
+		must.Equal(strings.Fields(`
+			a NEWLINE INDENT
+			b NEWLINE INDENT
+			c NEWLINE DEDENT
+			d NEWLINE DEDENT`),
+			must.Call(tokens, "a\n\tb\n\t  c\n\td\n", layout.Options{TabWidth: 4})[0])
+	}))
+	t.Run("mixed tabs and spaces", test.Case(func(ctx context.Context) {
+		_, err := tokens("a\n\tb\n        c\n", layout.Options{})
+		must.Equal("3:9: inconsistent use of tabs and spaces in indentation", err.Error())
+	}))
+	t.Run("unindent mismatch", test.Case(func(ctx context.Context) {
+		_, err := tokens("a\n    b\n  c\n", layout.Options{})
+		must.Equal("3:3: unindent does not match any outer indentation level", err.Error())
+	}))
+}
+
+func TestLayout_Savepoint(t *testing.T) {
+	t.Run("backtrack across line start", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a\n  b\nc")[0].(*parse.Source)
+		lines := layout.New(layout.Options{})
+		must.Equal(layout.None, lines.Peek(src))
+		src.Read1()
+		lines.StoreSavepoint(src)
+		must.Equal(layout.Newline, peekConsume(lines, src))
+		must.Equal(layout.Indent, peekConsume(lines, src))
+		must.Equal(byte('b'), src.Read1())
+		must.Equal(layout.Newline, peekConsume(lines, src))
+		must.Equal(layout.Dedent, peekConsume(lines, src))
+		lines.RollbackToSavepoint(src)
+		must.Equal(layout.Newline, peekConsume(lines, src))
+		must.Equal(layout.Indent, peekConsume(lines, src))
+		must.Equal(byte('b'), src.Read1())
+	}))
+}
+
+func peekConsume(lines *layout.Layout, src *parse.Source) layout.Kind {
+	lines.Peek(src)
+	return lines.Consume()
+}
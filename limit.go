@@ -0,0 +1,89 @@
+package parse
+
+import "fmt"
+
+// LeftoverError is reported by CloseExact if the frame is not read to the end
+type LeftoverError struct {
+	Position
+	// Left is how many bytes unread in the frame
+	Left int
+}
+
+func (err *LeftoverError) Error() string {
+	return fmt.Sprintf("%v: %d bytes left unread in the frame", err.Position, err.Left)
+}
+
+// frame is the part of parent source limited as the child source
+type frame struct {
+	parent *Source
+	// end is the index of parent readBytes after the frame
+	end int
+	// endOffset is the offset after the frame
+	endOffset int
+	// based tells if the line and column of child base are resolved from the parent
+	based  bool
+	closed bool
+}
+
+// Limit returns the child source of the next n bytes, like the TLV value or the HTTP body with Content-Length.
+// The child reports EOF at the boundary, so the nested parser can not read past the frame.
+// The frame is buffered by the parent and shared with the child without copying,
+// the parent should not be read until the child is closed, which advances the parent past the frame.
+// If fewer than n bytes left, the child gets the rest and io.ErrUnexpectedEOF is reported on the parent.
+// The positions of child are the same as the parent, n counts the bytes after transcoding.
+func (src *Source) Limit(n int) *Source {
+	if n < 0 {
+		n = 0
+	}
+	var data []byte
+	if src.Error() == nil {
+		data = src.PeekN(n)
+	}
+	offset := src.Offset()
+	return &Source{
+		readBytes:      data[:len(data):len(data)],
+		savepointStack: new(stack),
+		expectedOffset: -1,
+		// the line and column are resolved when needed, see resolveBase
+		base:      Position{Offset: offset},
+		utf8:      src.utf8,
+		columns:   src.columns,
+		bidiCheck: src.bidiCheck,
+		literals:  src.literals,
+		frame:     &frame{parent: src, end: src.nextIdx + len(data), endOffset: offset + len(data)},
+	}
+}
+
+// resolveBase resolves the line and column of the base from the parent
+func (src *Source) resolveBase() {
+	if src.frame != nil && !src.frame.based {
+		src.frame.based = true
+		src.base = src.frame.parent.PositionOf(src.base.Offset)
+	}
+}
+
+// Close advances the parent past the frame, the unread bytes in the frame are skipped.
+// It does nothing if the source is not limited or closed already.
+func (src *Source) Close() error {
+	return src.close(false)
+}
+
+// CloseExact like Close, but *LeftoverError is reported on the parent if the frame is not read to the end
+func (src *Source) CloseExact() error {
+	return src.close(true)
+}
+
+func (src *Source) close(exact bool) error {
+	if src.frame == nil || src.frame.closed {
+		return nil
+	}
+	src.frame.closed = true
+	parent := src.frame.parent
+	parent.nextIdx = src.frame.end
+	if left := src.frame.endOffset - src.Offset(); exact && left > 0 {
+		err := &LeftoverError{Position: src.Position(), Left: left}
+		parent.ReportError(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package parse_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_Limit(t *testing.T) {
+	t.Run("EOF at boundary", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, iotest.OneByteReader(strings.NewReader("ab\ncd|rest")), 1)[0].(*parse.Source)
+		src.ReadN(1)
+		child := src.Limit(4)
+		must.Equal("b\ncd", string(child.ReadAll()))
+		must.Equal(byte(0), child.Peek1())
+		must.Equal(io.ErrUnexpectedEOF, child.Error())
+		must.Equal(1, src.Offset())
+		must.Nil(child.Close())
+		must.Nil(src.Error())
+		must.Equal("|rest", string(src.ReadAll()))
+	}))
+	t.Run("position as parent", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "ab\ncd|")[0].(*parse.Source)
+		src.ReadN(1)
+		child := src.Limit(4)
+		must.Equal("1:2", child.Position().String())
+		child.ReadN(3)
+		must.Equal("2:2", child.Position().String())
+		must.Equal(4, child.Offset())
+	}))
+	t.Run("nested", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "[(ab)c]d")[0].(*parse.Source)
+		outer := src.Limit(7)
+		outer.Read1()
+		inner := outer.Limit(4)
+		must.Equal("(ab)", string(inner.ReadAll()))
+		must.Nil(inner.CloseExact())
+		must.Equal("c]", string(outer.ReadAll()))
+		must.Nil(outer.CloseExact())
+		must.Equal("d", string(src.ReadAll()))
+	}))
+	t.Run("leftover skipped", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abcd")[0].(*parse.Source)
+		child := src.Limit(3)
+		child.Read1()
+		must.Nil(child.Close())
+		must.Nil(child.Close())
+		must.Equal("d", string(src.ReadAll()))
+	}))
+	t.Run("leftover reported", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abcd")[0].(*parse.Source)
+		child := src.Limit(3)
+		child.Read1()
+		err := child.CloseExact()
+		must.Equal("1:2: 2 bytes left unread in the frame", err.Error())
+		must.Equal(err, src.Error())
+		must.Equal(3, src.Offset())
+	}))
+	t.Run("truncated frame", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		child := src.Limit(5)
+		must.Equal(io.ErrUnexpectedEOF, src.Error())
+		must.Nil(child.Error())
+		must.Equal(byte('a'), child.Read1())
+		must.Equal("bc", string(child.PeekN(2)))
+		must.Equal("bc", string(child.ReadAll()))
+		must.Nil(child.Close())
+		must.Equal(3, src.Offset())
+	}))
+	t.Run("iterate over child", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "1;2;3|rest")[0].(*parse.Source)
+		child := src.Limit(5)
+		iterator := parse.Iterate(child, &sumLexer{}, parse.IterateOptions{Separators: []byte{';'}})
+		var values []interface{}
+		for iterator.Next() {
+			values = append(values, iterator.Value())
+		}
+		must.Nil(iterator.Err())
+		must.Equal([]interface{}{1, 2, 3}, values)
+		must.Nil(child.CloseExact())
+		must.Equal("|rest", string(src.ReadAll()))
+	}))
+	t.Run("leftover after iterate", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "1;2;3|rest")[0].(*parse.Source)
+		child := src.Limit(5)
+		iterator := parse.Iterate(child, &sumLexer{}, parse.IterateOptions{Separators: []byte{';'}})
+		iterator.Next()
+		iterator.Next()
+		must.Equal("1:4: 2 bytes left unread in the frame", child.CloseExact().Error())
+		must.Equal(5, src.Offset())
+	}))
+	t.Run("empty frame", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		child := src.Limit(0)
+		child.Peek1()
+		must.Equal(io.ErrUnexpectedEOF, child.Error())
+		must.Nil(child.CloseExact())
+		must.Equal(byte('a'), src.Peek1())
+	}))
+}
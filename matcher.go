@@ -0,0 +1,161 @@
+package parse
+
+import "strconv"
+
+// MatcherOptions configure how the literals are matched
+type MatcherOptions struct {
+	// IgnoreCase matches the ASCII letters case-insensitively, like SQL keywords
+	IgnoreCase bool
+	// WordBoundary rejects the literal ending with word byte followed by word byte,
+	// so "in" does not match the start of "index".
+	// The word bytes are ASCII letters, digits, underscore and the non-ASCII bytes.
+	WordBoundary bool
+}
+
+// Matcher is the trie precompiled from the keywords or operators, for ExpectLongest
+type Matcher struct {
+	opts     MatcherOptions
+	literals []string
+	labels   []string
+	nodes    []matcherNode
+	longest  int
+}
+
+// matcherNode is the state of trie, next[b-lo] is the child node for byte b, 0 means none
+type matcherNode struct {
+	lo    byte
+	next  []int32
+	match int
+}
+
+// NewMatcher compiles the literals into matcher, nil options means the default.
+// If literals are duplicated, the first one wins. The empty literal never matches.
+func NewMatcher(opts *MatcherOptions, literals ...string) *Matcher {
+	m := &Matcher{
+		literals: literals,
+		labels:   make([]string, len(literals)),
+		nodes:    []matcherNode{{match: -1}},
+	}
+	if opts != nil {
+		m.opts = *opts
+	}
+	for i, literal := range literals {
+		m.labels[i] = strconv.Quote(literal)
+		if literal != "" {
+			m.add(i, literal)
+		}
+	}
+	return m
+}
+
+// Literal returns the i-th literal, as the index returned by ExpectLongest
+func (m *Matcher) Literal(i int) string {
+	return m.literals[i]
+}
+
+// Len returns how many literals in the matcher
+func (m *Matcher) Len() int {
+	return len(m.literals)
+}
+
+func (m *Matcher) add(which int, literal string) {
+	node := 0
+	for i := 0; i < len(literal); i++ {
+		b := m.fold(literal[i])
+		child := m.child(node, b)
+		if child == 0 {
+			child = len(m.nodes)
+			m.nodes = append(m.nodes, matcherNode{match: -1})
+			m.setChild(node, b, child)
+		}
+		node = child
+	}
+	if m.nodes[node].match < 0 {
+		m.nodes[node].match = which
+	}
+	if len(literal) > m.longest {
+		m.longest = len(literal)
+	}
+}
+
+func (m *Matcher) child(node int, b byte) int {
+	n := &m.nodes[node]
+	idx := int(b) - int(n.lo)
+	if idx < 0 || idx >= len(n.next) {
+		return 0
+	}
+	return int(n.next[idx])
+}
+
+func (m *Matcher) setChild(node int, b byte, child int) {
+	n := &m.nodes[node]
+	switch {
+	case len(n.next) == 0:
+		n.lo = b
+		n.next = []int32{0}
+	case b < n.lo:
+		next := make([]int32, int(n.lo)-int(b)+len(n.next))
+		copy(next[int(n.lo)-int(b):], n.next)
+		n.lo, n.next = b, next
+	case int(b)-int(n.lo) >= len(n.next):
+		n.next = append(n.next, make([]int32, int(b)-int(n.lo)-len(n.next)+1)...)
+	}
+	n.next[int(b)-int(n.lo)] = int32(child)
+}
+
+func (m *Matcher) fold(b byte) byte {
+	if m.opts.IgnoreCase && 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// match returns which literal is the longest prefix of data and its length, -1 if none
+func (m *Matcher) match(data []byte) (int, int) {
+	which, length := -1, 0
+	node := 0
+	for i := 0; ; i++ {
+		if match := m.nodes[node].match; match >= 0 && m.atBoundary(data, i) {
+			which, length = match, i
+		}
+		if i == len(data) {
+			break
+		}
+		if node = m.child(node, m.fold(data[i])); node == 0 {
+			break
+		}
+	}
+	return which, length
+}
+
+// atBoundary tells if the literal can end at data[i]
+func (m *Matcher) atBoundary(data []byte, i int) bool {
+	return !m.opts.WordBoundary || i == len(data) || !isWordByte(data[i-1]) || !isWordByte(data[i])
+}
+
+func isWordByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '_' || b >= 0x80
+}
+
+// ExpectLongest read the longest literal of matcher at the cursor, returns which literal matched.
+// Nothing is consumed if none matched, -1 is returned and all literals are reported as expected.
+func (src *Source) ExpectLongest(m *Matcher) int {
+	if src.Error() != nil {
+		return -1
+	}
+	lookahead := m.longest
+	if m.opts.WordBoundary {
+		lookahead++
+	}
+	ahead := src.peekUpTo(lookahead)
+	if src.Error() != nil {
+		return -1
+	}
+	which, length := m.match(ahead)
+	if which < 0 {
+		src.ReportExpected(m.labels...)
+		return -1
+	}
+	src.nextIdx += length
+	return which
+}
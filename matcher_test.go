@@ -0,0 +1,81 @@
+package parse_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_ExpectLongest(t *testing.T) {
+	operators := parse.NewMatcher(nil, "<", "<=", "<<", "<<=", "=", "==", "!=")
+	keywords := parse.NewMatcher(&parse.MatcherOptions{WordBoundary: true}, "in", "int", "if", "_")
+	sql := parse.NewMatcher(&parse.MatcherOptions{IgnoreCase: true, WordBoundary: true}, "select", "from")
+	testCases := []struct {
+		name    string
+		matcher *parse.Matcher
+		input   string
+		literal string
+		rest    string
+	}{
+		{"longest operator", operators, "<<=1", "<<=", "1"},
+		{"shorter operator", operators, "<<1", "<<", "1"},
+		{"operator at EOF", operators, "<=", "<=", ""},
+		{"operator falls back", operators, "<<-", "<<", "-"},
+		{"keyword", keywords, "in x", "in", " x"},
+		{"longer keyword", keywords, "int x", "int", " x"},
+		{"keyword before symbol", keywords, "if(", "if", "("},
+		{"keyword at EOF", keywords, "int", "int", ""},
+		{"not keyword", keywords, "index", "", "index"},
+		{"not keyword with unicode", keywords, "in\u00e9", "", "in\u00e9"},
+		{"underscore", keywords, "_x", "", "_x"},
+		{"ignore case", sql, "SeLeCt *", "select", " *"},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, testCase.input)[0].(*parse.Source)
+			which := src.ExpectLongest(testCase.matcher)
+			if testCase.literal == "" {
+				must.Equal(-1, which)
+			} else {
+				must.Equal(testCase.literal, testCase.matcher.Literal(which))
+			}
+			must.Nil(src.Error())
+			must.Equal(testCase.rest, string(src.PeekAll()))
+		}))
+	}
+	t.Run("report expected", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "x")[0].(*parse.Source)
+		must.Equal(-1, src.ExpectLongest(parse.NewMatcher(nil, "+", "+=")))
+		must.Equal(`1:1: expected one of "+", "+="; found "x"`, src.ExpectedError().Error())
+	}))
+	t.Run("reader", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, iotest.OneByteReader(strings.NewReader(">>>=")), 1)[0].(*parse.Source)
+		m := parse.NewMatcher(nil, ">", ">>", ">>>", ">>=", ">>>=")
+		must.Equal(">>>=", m.Literal(src.ExpectLongest(m)))
+	}))
+	t.Run("duplicated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "IF")[0].(*parse.Source)
+		m := parse.NewMatcher(&parse.MatcherOptions{IgnoreCase: true}, "if", "IF", "")
+		must.Equal(0, src.ExpectLongest(m))
+		must.Equal(3, m.Len())
+	}))
+}
+
+func BenchmarkSource_ExpectLongest(b *testing.B) {
+	m := parse.NewMatcher(&parse.MatcherOptions{WordBoundary: true},
+		"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+		"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+		"return", "select", "struct", "switch", "type", "var")
+	input := []byte("interface ")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		src, _ := parse.NewSourceBytes(input)
+		src.ExpectLongest(m)
+	}
+}
@@ -0,0 +1,101 @@
+package parse
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// lastRuleID is used to give each memoized rule an unique id
+var lastRuleID int32
+
+type memoKey struct {
+	ruleID int32
+	offset int
+}
+
+type memoEntry struct {
+	value         interface{}
+	end           int
+	err           error
+	evaluating    bool
+	leftRecursive bool
+}
+
+var errLeftRecursion = errors.New("left recursion")
+
+// Memoize turn the rule into a packrat rule.
+// The result and end offset is cached by (rule, offset) in the source,
+// so that backtracking by savepoint will not parse the same input twice.
+// Direct left recursion is supported by growing the seed (Warth et al.),
+// rule like expr := expr '-' term works without rewriting.
+func Memoize(rule Rule) Rule {
+	ruleID := atomic.AddInt32(&lastRuleID, 1)
+	return func(src *Source) interface{} {
+		return src.applyMemo(ruleID, rule)
+	}
+}
+
+// MemoizePrefix turn the prefix token into a memoized one
+func MemoizePrefix(token PrefixToken) PrefixToken {
+	return &memoPrefixToken{rule: Memoize(token.PrefixParse)}
+}
+
+type memoPrefixToken struct {
+	rule Rule
+}
+
+func (token *memoPrefixToken) PrefixParse(src *Source) interface{} {
+	return token.rule(src)
+}
+
+// ResetMemo drop all the cached rule results
+func (src *Source) ResetMemo() {
+	src.memo = nil
+}
+
+func (src *Source) applyMemo(ruleID int32, rule Rule) interface{} {
+	if src.Error() != nil {
+		return nil
+	}
+	start := src.nextIdx
+	key := memoKey{ruleID: ruleID, offset: start}
+	if entry := src.memo[key]; entry != nil {
+		if entry.evaluating {
+			entry.leftRecursive = true
+		}
+		return src.replayMemo(entry)
+	}
+	if src.memo == nil {
+		src.memo = map[memoKey]*memoEntry{}
+	}
+	// the seed is a failure, so that left recursive alternative fails at first
+	entry := &memoEntry{end: start, err: errLeftRecursion, evaluating: true}
+	src.memo[key] = entry
+	entry.value = rule(src)
+	entry.end = src.nextIdx
+	entry.err = src.Error()
+	entry.evaluating = false
+	if !entry.leftRecursive || entry.err != nil {
+		return entry.value
+	}
+	// grow the seed until the match can not be longer
+	for {
+		src.nextIdx = start
+		value := rule(src)
+		if src.Error() != nil || src.nextIdx <= entry.end {
+			src.nextIdx = entry.end
+			src.err = nil
+			return entry.value
+		}
+		entry.value = value
+		entry.end = src.nextIdx
+	}
+}
+
+func (src *Source) replayMemo(entry *memoEntry) interface{} {
+	src.nextIdx = entry.end
+	if entry.err != nil {
+		src.ReportError(entry.err)
+	}
+	return entry.value
+}
@@ -0,0 +1,92 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func digit(src *parse.Source) interface{} {
+	b := src.Peek1()
+	if src.Error() != nil {
+		return nil
+	}
+	if b < '0' || b > '9' {
+		src.ReportError(errNotDigit)
+		return nil
+	}
+	src.Read1()
+	return int(b - '0')
+}
+
+var errNotDigit = errors.New("not digit")
+
+func TestMemoize(t *testing.T) {
+	t.Run("parse once when backtracking", test.Case(func(ctx context.Context) {
+		called := 0
+		term := parse.Memoize(func(src *parse.Source) interface{} {
+			called++
+			return digit(src)
+		})
+		rule := parse.Choice(
+			parse.Sequence(term, parse.Literal("+")),
+			parse.Sequence(term, parse.Literal("-")),
+			parse.Sequence(term, parse.Literal("*")))
+		src, _ := parse.NewSourceString("1*")
+		must.Equal([]interface{}{1, "*"}, rule(src))
+		must.Equal(1, called)
+	}))
+	t.Run("replay failure", test.Case(func(ctx context.Context) {
+		called := 0
+		term := parse.Memoize(func(src *parse.Source) interface{} {
+			called++
+			return digit(src)
+		})
+		rule := parse.Choice(
+			parse.Sequence(term, parse.Literal("+")),
+			parse.Sequence(term, parse.Literal("-")))
+		src, _ := parse.NewSourceString("a-")
+		must.Nil(rule(src))
+		must.NotNil(src.Error())
+		must.Equal(1, called)
+	}))
+	t.Run("memoized prefix token", test.Case(func(ctx context.Context) {
+		token := parse.MemoizePrefix(&myToken{})
+		src, _ := parse.NewSourceString("ab")
+		src.StoreSavepoint()
+		must.Equal(uint8('a'), token.PrefixParse(src))
+		src.RollbackToSavepoint()
+		must.Equal(uint8('a'), token.PrefixParse(src))
+		must.Equal(uint8('b'), src.Peek1())
+	}))
+}
+
+func TestMemoize_LeftRecursion(t *testing.T) {
+	var expr parse.Rule
+	expr = parse.Memoize(parse.Choice(
+		parse.Action(parse.Sequence(parse.Ref(&expr), parse.Literal("-"), digit),
+			func(value interface{}) interface{} {
+				values := value.([]interface{})
+				return values[0].(int) - values[2].(int)
+			}),
+		digit))
+	t.Run("left associative", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("9-2-3")
+		must.Equal(4, expr(src))
+		must.Nil(src.Error())
+	}))
+	t.Run("seed only", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("9+")
+		must.Equal(9, expr(src))
+		must.Equal(byte('+'), src.Peek1())
+	}))
+	t.Run("no seed", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("-1")
+		must.Nil(expr(src))
+		must.NotNil(src.Error())
+	}))
+}
@@ -0,0 +1,179 @@
+// Package parallel parse the records of large input concurrently.
+// The input is split into chunks at record boundaries,
+// every chunk is parsed by parse.Iterate with its own source,
+// results are returned in input order with positions of the whole input.
+package parallel
+
+import (
+	"bytes"
+	"io/ioutil"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modern-go/parse"
+)
+
+// Boundary finds where the next record starts in data,
+// returns how many bytes before it, or -1 if not found.
+// The bytes before the boundary belong to previous chunk.
+type Boundary func(data []byte) int
+
+// Newline is the boundary of newline delimited records
+func Newline(data []byte) int {
+	idx := bytes.IndexByte(data, '\n')
+	if idx == -1 {
+		return -1
+	}
+	return idx + 1
+}
+
+// LexerFactory creates the lexer used by one worker,
+// so the lexer can keep state without locking.
+type LexerFactory func() parse.Lexer
+
+// Options configure how to split and parse the input
+type Options struct {
+	// Workers is the number of goroutines, default to runtime.NumCPU()
+	Workers int
+	// ChunkSize is the approximate bytes per chunk, default to 1MB
+	ChunkSize int
+	// Boundary finds the safe place to split, default to Newline
+	Boundary Boundary
+	// Separators between records, passed to parse.Iterate, default to newline
+	Separators []byte
+}
+
+// Result is the value of one record
+type Result struct {
+	Value interface{}
+	// Position where the record starts in the whole input
+	Position parse.Position
+}
+
+type chunk struct {
+	data     []byte
+	position parse.Position
+	results  []Result
+	err      error
+}
+
+// Parse parse the records of data concurrently, results are in input order.
+// If failed, the results before the first error are returned with the error.
+func Parse(data []byte, newLexer LexerFactory, opts Options) ([]Result, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 1 << 20
+	}
+	if opts.Boundary == nil {
+		opts.Boundary = Newline
+	}
+	if opts.Separators == nil {
+		opts.Separators = []byte{'\n'}
+	}
+	chunks := split(data, opts.ChunkSize, opts.Boundary)
+	// chunks after the first failed one are not needed
+	firstFailed := int64(len(chunks))
+	indices := make(chan int, len(chunks))
+	for i := range chunks {
+		indices <- i
+	}
+	close(indices)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers && i < len(chunks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lexer := newLexer()
+			for i := range indices {
+				if int64(i) > atomic.LoadInt64(&firstFailed) {
+					continue
+				}
+				chunk := chunks[i]
+				parseChunk(chunk, lexer, opts.Separators)
+				if chunk.err != nil {
+					markFailed(&firstFailed, int64(i))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	var results []Result
+	for _, chunk := range chunks {
+		results = append(results, chunk.results...)
+		if chunk.err != nil {
+			return results, chunk.err
+		}
+	}
+	return results, nil
+}
+
+// ParseFile read the whole file, then parse it like Parse
+func ParseFile(filename string, newLexer LexerFactory, opts Options) ([]Result, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, newLexer, opts)
+}
+
+func markFailed(firstFailed *int64, i int64) {
+	for {
+		failed := atomic.LoadInt64(firstFailed)
+		if i >= failed || atomic.CompareAndSwapInt64(firstFailed, failed, i) {
+			return
+		}
+	}
+}
+
+// split the data into chunks of about size bytes, the start position of every chunk is computed
+func split(data []byte, size int, boundary Boundary) []*chunk {
+	var chunks []*chunk
+	position := parse.Position{Line: 1, Column: 1}
+	for start := 0; start < len(data); {
+		end := start + size
+		if end >= len(data) {
+			end = len(data)
+		} else if cut := boundary(data[end:]); cut == -1 {
+			end = len(data)
+		} else {
+			end += cut
+		}
+		chunks = append(chunks, &chunk{data: data[start:end], position: position})
+		position = advance(position, data[start:end])
+		start = end
+	}
+	return chunks
+}
+
+// advance move the position forward by the bytes
+func advance(position parse.Position, data []byte) parse.Position {
+	position.Offset += len(data)
+	lines := bytes.Count(data, []byte{'\n'})
+	if lines == 0 {
+		position.Column += len(data)
+		return position
+	}
+	position.Line += lines
+	position.Column = len(data) - bytes.LastIndexByte(data, '\n')
+	return position
+}
+
+func parseChunk(chunk *chunk, lexer parse.Lexer, separators []byte) {
+	src, err := parse.NewSourceBytes(chunk.data)
+	if err != nil {
+		chunk.err = err
+		return
+	}
+	src.Rebase(chunk.position)
+	iterator := parse.Iterate(src, lexer, parse.IterateOptions{Separators: separators})
+	for iterator.Next() {
+		chunk.results = append(chunk.results, Result{
+			Value:    iterator.Value(),
+			Position: iterator.Position(),
+		})
+	}
+	chunk.err = iterator.Err()
+}
@@ -0,0 +1,83 @@
+package parallel_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/parallel"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+type numberLexer struct {
+}
+
+func newNumberLexer() parse.Lexer {
+	return &numberLexer{}
+}
+
+func (lexer *numberLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	b := src.Peek1()
+	if src.Error() == nil && b >= '0' && b <= '9' {
+		return lexer
+	}
+	src.ReportExpected("number")
+	return nil
+}
+
+func (lexer *numberLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	return nil, 0
+}
+
+func (lexer *numberLexer) PrefixParse(src *parse.Source) interface{} {
+	value := 0
+	for {
+		b := src.Peek1()
+		if src.Error() != nil || b < '0' || b > '9' {
+			return value
+		}
+		src.Read1()
+		value = value*10 + int(b-'0')
+	}
+}
+
+func records(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		buf.WriteString(strconv.Itoa(i))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	t.Run("input order", test.Case(func(ctx context.Context) {
+		results := must.Call(parallel.Parse, records(1000), parallel.LexerFactory(newNumberLexer),
+			parallel.Options{Workers: 4, ChunkSize: 16})[0].([]parallel.Result)
+		must.Equal(1000, len(results))
+		for i, result := range results {
+			must.Equal(i, result.Value)
+			must.Equal(i+1, result.Position.Line)
+		}
+		must.Equal(parse.Position{Offset: 3886, Line: 1000, Column: 1}, results[999].Position)
+	}))
+	t.Run("error position in whole input", test.Case(func(ctx context.Context) {
+		data := records(1000)
+		data[bytes.Index(data, []byte("\n500\n"))+2] = 'x'
+		results, err := parallel.Parse(data, newNumberLexer, parallel.Options{Workers: 4, ChunkSize: 16})
+		must.Equal(500, len(results))
+		must.Equal(`501:2: expected "\n"; found "x"`, err.Error())
+	}))
+	t.Run("custom boundary", test.Case(func(ctx context.Context) {
+		results := must.Call(parallel.Parse, []byte("1;22;333;4444"), parallel.LexerFactory(newNumberLexer),
+			parallel.Options{ChunkSize: 2, Separators: []byte{';'}, Boundary: func(data []byte) int {
+				return bytes.IndexByte(data, ';')
+			}})[0].([]parallel.Result)
+		must.Equal(4, len(results))
+		must.Equal(4444, results[3].Value)
+		must.Equal(9, results[3].Position.Offset)
+	}))
+}
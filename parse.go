@@ -13,21 +13,80 @@ func String(input string, lexer Lexer) (interface{}, error) {
 		return nil, err
 	}
 	left := Parse(src, lexer, 0)
-	if src.Error() != nil {
-		if src.Error() == io.EOF {
-			return left, nil
-		}
-		return nil, src.Error()
+	if err := src.parseError(); err != nil {
+		return nil, err
 	}
 	return left, nil
 }
 
+// parseError tells if the parsing failed, reaching the end of input is not failure
+func (src *Source) parseError() error {
+	switch src.Error() {
+	case nil, io.EOF:
+		return nil
+	case io.ErrUnexpectedEOF:
+		// peeked beyond the end, but all input has been consumed
+		if src.nextIdx == len(src.readBytes) {
+			return nil
+		}
+	}
+	return src.Error()
+}
+
 // Parse parse the source with provided lexer, might call this recursively.
 // If precedence > 0, some infix will be skipped due to precedence.
+// The lexer is pushed to the lexer stack of source while parsing,
+// if lexer is nil, the current lexer of source is used.
 func Parse(src *Source, lexer Lexer, precedence int) interface{} {
+	if lexer == nil {
+		lexer = src.Lexer()
+		if lexer == nil {
+			src.ReportError(errNoLexer)
+			return nil
+		}
+	}
+	src.PushLexer(lexer)
+	defer src.PopLexer()
+	if src.tree != nil {
+		return src.tree.parse(src, lexer, precedence)
+	}
+	return parseLoop(src, lexer, precedence)
+}
+
+var errNoLexer = errors.New("no lexer in stack")
+
+// PushLexer switch the source to another lexer, like string mode of template.
+// The token can call Parse with nil lexer to continue with the current lexer.
+func (src *Source) PushLexer(lexer Lexer) {
+	src.lexers = append(src.lexers, lexer)
+}
+
+// PopLexer switch back to the previous lexer, the popped lexer is returned
+func (src *Source) PopLexer() Lexer {
+	if len(src.lexers) == 0 {
+		src.ReportError(errNoLexer)
+		return nil
+	}
+	last := len(src.lexers) - 1
+	lexer := src.lexers[last]
+	src.lexers[last] = nil
+	src.lexers = src.lexers[:last]
+	return lexer
+}
+
+// Lexer returns the current lexer, nil if the lexer stack is empty
+func (src *Source) Lexer() Lexer {
+	if len(src.lexers) == 0 {
+		return nil
+	}
+	return src.lexers[len(src.lexers)-1]
+}
+
+func parseLoop(src *Source, lexer Lexer, precedence int) interface{} {
 	token := lexer.PrefixToken(src)
 	if token == nil {
-		src.ReportError(errors.New("can not parse"))
+		src.ReportExpected()
+		src.ReportError(src.ExpectedError())
 		return nil
 	}
 	InfoLogger.Println("prefix", ">>>", reflect.TypeOf(token))
@@ -16,7 +16,7 @@ func TestString(t *testing.T) {
 	}))
 	t.Run("can not parse", test.Case(func(ctx context.Context) {
 		parsed, err := parse.String("bc", &myLexer{})
-		must.NotNil(err)
+		must.Equal(`1:1: unexpected "b"`, err.Error())
 		must.Nil(parsed)
 	}))
 	t.Run("EOF", test.Case(func(ctx context.Context) {
@@ -48,3 +48,139 @@ func (token *myToken) PrefixParse(src *parse.Source) interface{} {
 	b := src.Read1()
 	return b
 }
+
+// templateLexer parse text like `a ${b + c} d`, the expression is parsed by exprLexer
+type templateLexer struct {
+	expr *exprLexer
+}
+
+func (lexer *templateLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	token, _ := lexer.InfixToken(src)
+	if token == nil {
+		return nil
+	}
+	return token.(parse.PrefixToken)
+}
+
+func (lexer *templateLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	b := src.Peek1()
+	if src.Error() != nil || b == '}' {
+		return nil, 0
+	}
+	if b == '$' {
+		return &interpolationToken{lexer: lexer}, 1
+	}
+	return &textToken{}, 1
+}
+
+type textToken struct {
+}
+
+func (token *textToken) PrefixParse(src *parse.Source) interface{} {
+	text := ""
+	for {
+		b := src.Peek1()
+		if src.Error() != nil || b == '$' || b == '}' {
+			return text
+		}
+		text += string(src.Read1())
+	}
+}
+
+func (token *textToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	return left.(string) + token.PrefixParse(src).(string)
+}
+
+type interpolationToken struct {
+	lexer *templateLexer
+}
+
+func (token *interpolationToken) PrefixParse(src *parse.Source) interface{} {
+	if !src.Expect2('$', '{') {
+		return nil
+	}
+	value := parse.Parse(src, token.lexer.expr, 0)
+	if !src.Expect1('}') {
+		return nil
+	}
+	return value
+}
+
+func (token *interpolationToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	value := token.PrefixParse(src)
+	if value == nil {
+		return nil
+	}
+	return left.(string) + value.(string)
+}
+
+// exprLexer parse variables concatenated by +, the tokens continue with current lexer
+type exprLexer struct {
+	vars map[byte]string
+}
+
+func (lexer *exprLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	for src.Peek1() == ' ' && src.Error() == nil {
+		src.Read1()
+	}
+	if src.Error() != nil {
+		return nil
+	}
+	return &varToken{vars: lexer.vars}
+}
+
+func (lexer *exprLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	for src.Peek1() == ' ' && src.Error() == nil {
+		src.Read1()
+	}
+	if src.Peek1() == '+' && src.Error() == nil {
+		return &concatToken{}, 1
+	}
+	return nil, 0
+}
+
+type varToken struct {
+	vars map[byte]string
+}
+
+func (token *varToken) PrefixParse(src *parse.Source) interface{} {
+	return token.vars[src.Read1()]
+}
+
+type concatToken struct {
+}
+
+func (token *concatToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	src.Read1()
+	right := parse.Parse(src, nil, 1)
+	if right == nil {
+		return nil
+	}
+	return left.(string) + right.(string)
+}
+
+func TestSource_PushLexer(t *testing.T) {
+	t.Run("switch lexer in token", test.Case(func(ctx context.Context) {
+		lexer := &templateLexer{expr: &exprLexer{vars: map[byte]string{'b': "B", 'c': "C"}}}
+		parsed := must.Call(parse.String, "a ${b + c} d ${c}", lexer)[0]
+		must.Equal("a BC d C", parsed)
+	}))
+	t.Run("current lexer", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a")[0].(*parse.Source)
+		must.Nil(src.Lexer())
+		lexer := &myLexer{}
+		src.PushLexer(lexer)
+		must.Equal(uint8('a'), parse.Parse(src, nil, 0))
+		must.Equal(lexer, src.PopLexer())
+		must.Nil(src.Lexer())
+		must.Nil(parse.Parse(src, nil, 0))
+		must.Equal("no lexer in stack", src.Error().Error())
+	}))
+	t.Run("rollback drops pushed lexer", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a")[0].(*parse.Source)
+		src.StoreSavepoint()
+		src.PushLexer(&myLexer{})
+		src.RollbackToSavepoint()
+		must.Nil(src.Lexer())
+	}))
+}
@@ -0,0 +1,201 @@
+package peg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a positioned error found in the grammar
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// ErrorList is all of the errors found in the grammar
+type ErrorList []*Error
+
+func (list ErrorList) Error() string {
+	messages := make([]string, len(list))
+	for i, err := range list {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// checker report the errors can only be found after the whole grammar is parsed
+type checker struct {
+	scanner *scanner
+	grammar *grammar
+	actions Actions
+	rules   map[string]*ruleNode
+	// succeeds tells if the rule never fails, nil when the rule is being computed
+	succeeds map[string]*bool
+	// nullables tells if the rule can match empty input, nil when the rule is being computed
+	nullables map[string]*bool
+}
+
+func checkGrammar(scanner *scanner, grammar *grammar, actions Actions) {
+	c := &checker{
+		scanner:   scanner,
+		grammar:   grammar,
+		actions:   actions,
+		rules:     map[string]*ruleNode{},
+		succeeds:  map[string]*bool{},
+		nullables: map[string]*bool{},
+	}
+	for _, rule := range grammar.rules {
+		if c.rules[rule.name] != nil {
+			scanner.errorf(rule.offset, "rule %s redefined", rule.name)
+			continue
+		}
+		c.rules[rule.name] = rule
+	}
+	if len(grammar.rules) == 0 {
+		scanner.errorf(0, "no rule defined")
+	} else if grammar.start == "" {
+		grammar.start = grammar.rules[0].name
+	} else if c.rules[grammar.start] == nil {
+		scanner.errorf(grammar.startOffset, "undefined rule %s", grammar.start)
+	}
+	for _, rule := range grammar.rules {
+		c.check(rule.expr)
+	}
+}
+
+func (c *checker) check(expr node) {
+	switch expr := expr.(type) {
+	case *choiceNode:
+		for i, alternative := range expr.alternatives {
+			c.check(alternative)
+			for _, previous := range expr.alternatives[:i] {
+				if c.shadows(previous, alternative) {
+					c.scanner.errorf(alternative.offset, "unreachable alternative")
+					break
+				}
+			}
+		}
+	case *sequenceNode:
+		for _, item := range expr.items {
+			c.check(item)
+		}
+		if expr.action != "" && c.actions[expr.action] == nil {
+			c.scanner.errorf(expr.actionOffset, "undefined action @%s", expr.action)
+		}
+	case *refNode:
+		if c.rules[expr.name] == nil {
+			c.scanner.errorf(expr.offset, "undefined rule %s", expr.name)
+		}
+	case *repeatNode:
+		c.check(expr.expr)
+		if c.nullable(expr.expr) {
+			c.scanner.errorf(expr.offset, "repetition of expression matching empty input")
+		}
+	case *optionalNode:
+		c.check(expr.expr)
+	case *lookaheadNode:
+		c.check(expr.expr)
+	case *operatorsNode:
+		c.check(expr.operand)
+		for _, level := range expr.levels {
+			for _, operator := range level.operators {
+				c.check(operator)
+			}
+		}
+	}
+}
+
+// shadows tells if the later alternative can never be tried,
+// because the previous one always succeeds, or matches whenever the later one does
+func (c *checker) shadows(previous *sequenceNode, later *sequenceNode) bool {
+	if c.alwaysSucceeds(previous) {
+		return true
+	}
+	if len(previous.items) != 1 || len(later.items) == 0 {
+		return false
+	}
+	previousLiteral, isLiteral := previous.items[0].(*literalNode)
+	if !isLiteral {
+		return false
+	}
+	laterLiteral, isLiteral := later.items[0].(*literalNode)
+	return isLiteral && strings.HasPrefix(laterLiteral.text, previousLiteral.text)
+}
+
+// alwaysSucceeds tells if the expression matches any input, including empty input
+func (c *checker) alwaysSucceeds(expr node) bool {
+	switch expr := expr.(type) {
+	case *choiceNode:
+		for _, alternative := range expr.alternatives {
+			if c.alwaysSucceeds(alternative) {
+				return true
+			}
+		}
+	case *sequenceNode:
+		for _, item := range expr.items {
+			if !c.alwaysSucceeds(item) {
+				return false
+			}
+		}
+		return true
+	case *literalNode:
+		return expr.text == ""
+	case *repeatNode:
+		return expr.min == 0 || c.alwaysSucceeds(expr.expr)
+	case *optionalNode:
+		return true
+	case *lookaheadNode:
+		return !expr.negated && c.alwaysSucceeds(expr.expr)
+	case *refNode:
+		return c.ruleProperty(c.succeeds, expr.name, c.alwaysSucceeds)
+	}
+	return false
+}
+
+// nullable tells if the expression can match without consuming any input
+func (c *checker) nullable(expr node) bool {
+	switch expr := expr.(type) {
+	case *choiceNode:
+		for _, alternative := range expr.alternatives {
+			if c.nullable(alternative) {
+				return true
+			}
+		}
+	case *sequenceNode:
+		for _, item := range expr.items {
+			if !c.nullable(item) {
+				return false
+			}
+		}
+		return true
+	case *literalNode:
+		return expr.text == ""
+	case *repeatNode:
+		return expr.min == 0 || c.nullable(expr.expr)
+	case *optionalNode, *lookaheadNode:
+		return true
+	case *refNode:
+		return c.ruleProperty(c.nullables, expr.name, c.nullable)
+	}
+	return false
+}
+
+// ruleProperty compute the property of the rule once, recursive reference is assumed to be false
+func (c *checker) ruleProperty(cache map[string]*bool, name string, compute func(expr node) bool) bool {
+	rule := c.rules[name]
+	if rule == nil {
+		return false
+	}
+	result, computed := cache[name]
+	if computed {
+		return result != nil && *result
+	}
+	cache[name] = nil
+	value := compute(rule.expr)
+	cache[name] = &value
+	return value
+}
@@ -0,0 +1,46 @@
+package peg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse/peg"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func compileError(grammar string, actions peg.Actions) string {
+	_, err := peg.Compile(grammar, actions)
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestCompile_Check(t *testing.T) {
+	t.Run("undefined rule", test.Case(func(ctx context.Context) {
+		must.Equal("1:10: undefined rule B", compileError(`A <- 'a' B`, nil))
+	}))
+	t.Run("undefined start rule", test.Case(func(ctx context.Context) {
+		must.Equal("1:8: undefined rule B", compileError(`%start B A <- 'a'`, nil))
+	}))
+	t.Run("redefined rule", test.Case(func(ctx context.Context) {
+		must.Equal("2:1: rule A redefined", compileError("A <- 'a'\nA <- 'b'", nil))
+	}))
+	t.Run("undefined action", test.Case(func(ctx context.Context) {
+		must.Equal("1:10: undefined action @b", compileError(`A <- 'a' @b`, nil))
+	}))
+	t.Run("unreachable after always succeeding alternative", test.Case(func(ctx context.Context) {
+		must.Equal("1:16: unreachable alternative", compileError(`A <- 'a' / B / 'b'
+			B <- 'c'?`, nil))
+	}))
+	t.Run("unreachable after prefix literal", test.Case(func(ctx context.Context) {
+		must.Equal("1:12: unreachable alternative", compileError(`A <- 'a' / 'ab' / 'b'`, nil))
+	}))
+	t.Run("repetition of empty match", test.Case(func(ctx context.Context) {
+		must.Equal("1:6: repetition of expression matching empty input", compileError(`A <- ('a'?)*`, nil))
+	}))
+	t.Run("all errors reported", test.Case(func(ctx context.Context) {
+		must.Equal("1:6: undefined rule B\n2:6: undefined rule C", compileError("A <- B\nD <- C", nil))
+	}))
+}
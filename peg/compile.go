@@ -0,0 +1,257 @@
+package peg
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/modern-go/parse"
+)
+
+var errNotMatched = errors.New("not matched")
+
+type compiler struct {
+	actions Actions
+	rules   map[string]*parse.Rule
+}
+
+func (c *compiler) compile(expr node) parse.Rule {
+	switch expr := expr.(type) {
+	case *choiceNode:
+		if len(expr.alternatives) == 1 {
+			return c.compile(expr.alternatives[0])
+		}
+		alternatives := make([]parse.Rule, len(expr.alternatives))
+		for i, alternative := range expr.alternatives {
+			alternatives[i] = c.compile(alternative)
+		}
+		return parse.Choice(alternatives...)
+	case *sequenceNode:
+		return c.compileSequence(expr)
+	case *literalNode:
+		return parse.Literal(expr.text)
+	case *classNode:
+		return matchRune(expr.text, expr.match)
+	case *anyNode:
+		return matchRune("any character", func(r rune) bool {
+			return true
+		})
+	case *refNode:
+		return parse.Ref(c.rules[expr.name])
+	case *repeatNode:
+		return repeat(c.compile(expr.expr), expr.min)
+	case *optionalNode:
+		return optional(c.compile(expr.expr))
+	case *lookaheadNode:
+		return lookahead(c.compile(expr.expr), expr.negated)
+	case *operatorsNode:
+		return c.compileOperators(expr)
+	}
+	panic("unknown node")
+}
+
+func (c *compiler) compileSequence(sequence *sequenceNode) parse.Rule {
+	items := make([]parse.Rule, len(sequence.items))
+	for i, item := range sequence.items {
+		items[i] = c.compile(item)
+	}
+	action := c.actions[sequence.action]
+	if action == nil {
+		switch len(items) {
+		case 0:
+			return func(src *parse.Source) interface{} {
+				return nil
+			}
+		case 1:
+			return items[0]
+		}
+		return parse.Sequence(items...)
+	}
+	return parse.Action(parse.Sequence(items...), func(value interface{}) interface{} {
+		return action(value.([]interface{}))
+	})
+}
+
+func (class *classNode) match(r rune) bool {
+	for _, rng := range class.ranges {
+		if r >= rng.low && r <= rng.high {
+			return !class.negated
+		}
+	}
+	return class.negated
+}
+
+// matchRune match one rune, the matched rune is returned as string.
+// The label is reported as expected if not matched.
+func matchRune(label string, match func(r rune) bool) parse.Rule {
+	return func(src *parse.Source) interface{} {
+		if src.Error() != nil {
+			return nil
+		}
+		r, n := src.PeekRune()
+		if src.Error() != nil || (r == utf8.RuneError && n == 1) || !match(r) {
+			src.ReportExpected(label)
+			src.ReportError(errNotMatched)
+			return nil
+		}
+		return string(src.ReadN(n))
+	}
+}
+
+// repeat match the rule as many times as possible, values are returned as []interface{}
+func repeat(rule parse.Rule, min int) parse.Rule {
+	return func(src *parse.Source) interface{} {
+		if src.Error() != nil {
+			return nil
+		}
+		values := []interface{}{}
+		for {
+			src.StoreSavepoint()
+			value := rule(src)
+			if src.Error() != nil {
+				src.RollbackToSavepoint()
+				break
+			}
+			src.DeleteSavepoint()
+			values = append(values, value)
+		}
+		if len(values) < min {
+			src.ReportError(errNotMatched)
+			return nil
+		}
+		return values
+	}
+}
+
+// optional match the rule or nothing, nil is returned if not matched
+func optional(rule parse.Rule) parse.Rule {
+	return func(src *parse.Source) interface{} {
+		if src.Error() != nil {
+			return nil
+		}
+		src.StoreSavepoint()
+		value := rule(src)
+		if src.Error() != nil {
+			src.RollbackToSavepoint()
+			return nil
+		}
+		src.DeleteSavepoint()
+		return value
+	}
+}
+
+// lookahead match the rule without consuming the input
+func lookahead(rule parse.Rule, negated bool) parse.Rule {
+	return func(src *parse.Source) interface{} {
+		if src.Error() != nil {
+			return nil
+		}
+		if matches(src, rule) == negated {
+			src.ReportError(errNotMatched)
+		}
+		return nil
+	}
+}
+
+// matches tells if the rule matches, the cursor is not moved
+func matches(src *parse.Source, rule parse.Rule) bool {
+	src.StoreSavepoint()
+	rule(src)
+	matched := src.Error() == nil
+	src.RollbackToSavepoint()
+	return matched
+}
+
+func (c *compiler) compileOperators(operators *operatorsNode) parse.Rule {
+	lexer := &operatorLexer{operand: &operandToken{rule: c.compile(operators.operand)}}
+	for i, level := range operators.levels {
+		for _, operator := range level.operators {
+			token := &operatorToken{
+				lexer:      lexer,
+				kind:       level.kind,
+				precedence: i + 1,
+				match:      c.compile(operator.items[0]),
+				action:     c.actions[operator.action],
+			}
+			if level.kind == operatorPrefix {
+				lexer.prefix = append(lexer.prefix, token)
+			} else {
+				lexer.infix = append(lexer.infix, token)
+			}
+		}
+	}
+	return func(src *parse.Source) interface{} {
+		return parse.Parse(src, lexer, 0)
+	}
+}
+
+// operatorLexer maps the operator-precedence section onto the pratt parser
+type operatorLexer struct {
+	operand *operandToken
+	prefix  []*operatorToken
+	infix   []*operatorToken
+}
+
+func (lexer *operatorLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	if src.Error() != nil {
+		return lexer.operand
+	}
+	for _, token := range lexer.prefix {
+		if matches(src, token.match) {
+			return token
+		}
+	}
+	return lexer.operand
+}
+
+func (lexer *operatorLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	for _, token := range lexer.infix {
+		if matches(src, token.match) {
+			return token, token.precedence
+		}
+	}
+	return nil, 0
+}
+
+type operandToken struct {
+	rule parse.Rule
+}
+
+func (token *operandToken) PrefixParse(src *parse.Source) interface{} {
+	return token.rule(src)
+}
+
+type operatorToken struct {
+	lexer      *operatorLexer
+	kind       operatorKind
+	precedence int
+	match      parse.Rule
+	action     Action
+}
+
+func (token *operatorToken) PrefixParse(src *parse.Source) interface{} {
+	operator := token.match(src)
+	operand := parse.Parse(src, token.lexer, token.precedence)
+	return token.apply(src, operator, operand)
+}
+
+func (token *operatorToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	operator := token.match(src)
+	switch token.kind {
+	case operatorPostfix:
+		return token.apply(src, left, operator)
+	case operatorRight:
+		return token.apply(src, left, operator, parse.Parse(src, token.lexer, token.precedence-1))
+	}
+	return token.apply(src, left, operator, parse.Parse(src, token.lexer, token.precedence))
+}
+
+// apply call the action with values in the order of appearance
+func (token *operatorToken) apply(src *parse.Source, values ...interface{}) interface{} {
+	if src.Error() != nil {
+		return nil
+	}
+	if token.action == nil {
+		return values
+	}
+	return token.action(values)
+}
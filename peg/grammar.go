@@ -0,0 +1,314 @@
+package peg
+
+// the grammar is parsed into these nodes before compiling
+
+type grammar struct {
+	rules       []*ruleNode
+	start       string
+	startOffset int
+}
+
+type ruleNode struct {
+	offset int
+	name   string
+	expr   node
+}
+
+type node interface{}
+
+type choiceNode struct {
+	alternatives []*sequenceNode
+}
+
+type sequenceNode struct {
+	offset       int
+	items        []node
+	action       string
+	actionOffset int
+}
+
+type literalNode struct {
+	text string
+}
+
+type runeRange struct {
+	low  rune
+	high rune
+}
+
+type classNode struct {
+	text    string
+	ranges  []runeRange
+	negated bool
+}
+
+type anyNode struct {
+}
+
+type refNode struct {
+	offset int
+	name   string
+}
+
+type repeatNode struct {
+	offset int
+	expr   node
+	min    int
+}
+
+type optionalNode struct {
+	expr node
+}
+
+type lookaheadNode struct {
+	expr    node
+	negated bool
+}
+
+type operatorKind int
+
+const (
+	operatorLeft operatorKind = iota
+	operatorRight
+	operatorPrefix
+	operatorPostfix
+)
+
+var operatorKinds = map[string]operatorKind{
+	"left":    operatorLeft,
+	"right":   operatorRight,
+	"prefix":  operatorPrefix,
+	"postfix": operatorPostfix,
+}
+
+// operatorsNode is the operator-precedence section, levels are from low to high precedence
+type operatorsNode struct {
+	operand node
+	levels  []*operatorLevel
+}
+
+type operatorLevel struct {
+	kind      operatorKind
+	operators []*sequenceNode
+}
+
+type grammarParser struct {
+	scanner *scanner
+	tok     token
+	grammar *grammar
+}
+
+func parseGrammar(text string) (*grammar, ErrorList) {
+	parser := &grammarParser{
+		scanner: &scanner{text: text},
+		grammar: &grammar{},
+	}
+	parser.advance()
+	for parser.tok.kind != tokEOF {
+		switch parser.tok.kind {
+		case tokDefine:
+			parser.parseRule()
+		case tokDirective:
+			parser.parseDirective()
+		default:
+			parser.errorf("expected rule definition")
+			parser.advance()
+		}
+	}
+	return parser.grammar, parser.scanner.errors
+}
+
+func (parser *grammarParser) advance() {
+	parser.tok = parser.scanner.next()
+}
+
+func (parser *grammarParser) errorf(format string, args ...interface{}) {
+	parser.scanner.errorf(parser.tok.offset, format, args...)
+}
+
+func (parser *grammarParser) isPunct(punct string) bool {
+	return parser.tok.kind == tokPunct && parser.tok.text == punct
+}
+
+func (parser *grammarParser) expectPunct(punct string) {
+	if !parser.isPunct(punct) {
+		parser.errorf("expected %q", punct)
+		return
+	}
+	parser.advance()
+}
+
+// parseHead parse "Name <-", the scanner is switched to EBNF mode by = or ::=
+func (parser *grammarParser) parseHead() *ruleNode {
+	rule := &ruleNode{offset: parser.tok.offset, name: parser.tok.text}
+	parser.advance()
+	parser.scanner.ebnf = parser.tok.text != "<-"
+	parser.advance()
+	return rule
+}
+
+func (parser *grammarParser) parseRule() {
+	rule := parser.parseHead()
+	rule.expr = parser.parseChoice()
+	if parser.isPunct(";") {
+		parser.advance()
+	}
+	parser.scanner.ebnf = false
+	parser.grammar.rules = append(parser.grammar.rules, rule)
+}
+
+func (parser *grammarParser) parseDirective() {
+	directive := parser.tok.text
+	switch directive {
+	case "start":
+		parser.advance()
+		if parser.tok.kind != tokIdent {
+			parser.errorf("expected rule name after %%start")
+			return
+		}
+		parser.grammar.start = parser.tok.text
+		parser.grammar.startOffset = parser.tok.offset
+		parser.advance()
+	case "operators":
+		parser.advance()
+		if parser.tok.kind != tokDefine {
+			parser.errorf("expected rule definition after %%operators")
+			return
+		}
+		rule := parser.parseHead()
+		operators := &operatorsNode{operand: parser.parsePrefix()}
+		for parser.tok.kind == tokDirective {
+			kind, found := operatorKinds[parser.tok.text]
+			if !found {
+				break
+			}
+			parser.advance()
+			level := &operatorLevel{kind: kind}
+			for parser.startsPrimary() {
+				operator := &sequenceNode{offset: parser.tok.offset}
+				operator.items = []node{parser.parsePrefix()}
+				parser.parseAction(operator)
+				level.operators = append(level.operators, operator)
+			}
+			if len(level.operators) == 0 {
+				parser.errorf("expected operator")
+			}
+			operators.levels = append(operators.levels, level)
+		}
+		parser.scanner.ebnf = false
+		rule.expr = operators
+		parser.grammar.rules = append(parser.grammar.rules, rule)
+	default:
+		parser.errorf("unknown directive %%%s", directive)
+		parser.advance()
+	}
+}
+
+func (parser *grammarParser) parseChoice() node {
+	choice := &choiceNode{}
+	choice.alternatives = append(choice.alternatives, parser.parseSequence())
+	for parser.isPunct("/") || parser.isPunct("|") {
+		parser.advance()
+		choice.alternatives = append(choice.alternatives, parser.parseSequence())
+	}
+	return choice
+}
+
+func (parser *grammarParser) startsPrimary() bool {
+	switch parser.tok.kind {
+	case tokIdent, tokLiteral, tokClass:
+		return true
+	case tokPunct:
+		switch parser.tok.text {
+		case "&", "!", "(", ".":
+			return true
+		case "[", "{":
+			return parser.scanner.ebnf
+		}
+	}
+	return false
+}
+
+func (parser *grammarParser) parseSequence() *sequenceNode {
+	sequence := &sequenceNode{offset: parser.tok.offset}
+	for parser.startsPrimary() {
+		sequence.items = append(sequence.items, parser.parsePrefix())
+	}
+	parser.parseAction(sequence)
+	return sequence
+}
+
+func (parser *grammarParser) parseAction(sequence *sequenceNode) {
+	if parser.tok.kind == tokAction {
+		sequence.action = parser.tok.text
+		sequence.actionOffset = parser.tok.offset
+		parser.advance()
+	}
+}
+
+func (parser *grammarParser) parsePrefix() node {
+	if parser.isPunct("&") || parser.isPunct("!") {
+		negated := parser.tok.text == "!"
+		parser.advance()
+		return &lookaheadNode{expr: parser.parseSuffix(), negated: negated}
+	}
+	return parser.parseSuffix()
+}
+
+func (parser *grammarParser) parseSuffix() node {
+	offset := parser.tok.offset
+	expr := parser.parsePrimary()
+	for parser.tok.kind == tokPunct {
+		switch parser.tok.text {
+		case "*":
+			expr = &repeatNode{offset: offset, expr: expr, min: 0}
+		case "+":
+			expr = &repeatNode{offset: offset, expr: expr, min: 1}
+		case "?":
+			expr = &optionalNode{expr: expr}
+		default:
+			return expr
+		}
+		parser.advance()
+	}
+	return expr
+}
+
+func (parser *grammarParser) parsePrimary() node {
+	tok := parser.tok
+	switch tok.kind {
+	case tokIdent:
+		parser.advance()
+		return &refNode{offset: tok.offset, name: tok.text}
+	case tokLiteral:
+		parser.advance()
+		return &literalNode{text: tok.text}
+	case tokClass:
+		parser.advance()
+		return tok.class
+	case tokPunct:
+		switch tok.text {
+		case ".":
+			parser.advance()
+			return &anyNode{}
+		case "(":
+			parser.advance()
+			expr := parser.parseChoice()
+			parser.expectPunct(")")
+			return expr
+		case "[":
+			parser.advance()
+			expr := parser.parseChoice()
+			parser.expectPunct("]")
+			return &optionalNode{expr: expr}
+		case "{":
+			parser.advance()
+			expr := parser.parseChoice()
+			parser.expectPunct("}")
+			return &repeatNode{offset: tok.offset, expr: expr, min: 0}
+		}
+	}
+	parser.errorf("expected expression")
+	parser.advance()
+	return &sequenceNode{offset: tok.offset}
+}
@@ -0,0 +1,43 @@
+package peg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse/peg"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestCompile_Syntax(t *testing.T) {
+	t.Run("missing definition", test.Case(func(ctx context.Context) {
+		must.Equal("1:1: expected rule definition", compileError(`'a'`, nil))
+	}))
+	t.Run("literal not terminated", test.Case(func(ctx context.Context) {
+		must.Equal("1:6: literal not terminated", compileError(`A <- 'a`, nil))
+	}))
+	t.Run("class not terminated", test.Case(func(ctx context.Context) {
+		must.Equal("1:6: character class not terminated", compileError(`A <- [a-z`, nil))
+	}))
+	t.Run("group not closed", test.Case(func(ctx context.Context) {
+		must.Equal(`1:10: expected ")"`, compileError(`A <- ('a'`, nil))
+	}))
+	t.Run("unknown directive", test.Case(func(ctx context.Context) {
+		must.Equal("1:1: unknown directive %end", compileError(`%end A <- 'a'`, nil))
+	}))
+	t.Run("escapes and classes", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			A <- '\x41\t' [^\]a-c] "中" .
+		`, nil)[0].(*peg.Parser)
+		must.Equal([]interface{}{"A\t", "d", "中", "!"}, must.Call(parser.String, "A\td中!")[0])
+		_, err := parser.String("A\tb中!")
+		must.NotNil(err)
+	}))
+	t.Run("comment", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			# the start rule
+			A <- 'a' # trailing comment
+		`, nil)[0].(*peg.Parser)
+		must.Equal("a", must.Call(parser.String, "a")[0])
+	}))
+}
@@ -0,0 +1,126 @@
+// Package peg build parser from PEG or EBNF grammar text at runtime.
+//
+// The grammar is a list of rules, # starts a comment to the end of line.
+//
+//	Sum     <- Product ('+' Product)* @sum     # PEG rule
+//	Product = Number { '*' Number } @product ; # EBNF rule, = or ::= can be used
+//	Number  <- [0-9]+ @number
+//
+// PEG rules support / choice, * + ? repetition, & ! lookahead, . any rune,
+// [a-z] character class and ( ) group.
+// EBNF rules support | choice, [ ] optional, { } repetition and ( ) group.
+// The rule ends at the start of next rule, or optionally at ;.
+//
+// @name after a sequence references a Go callback in Actions,
+// it is called with the values of the sequence items.
+// The value of literal, character class and any rune is the matched string,
+// the value of repetition is []interface{}, the value of optional is nil if not matched.
+// Sequence of multiple items without action has the value []interface{}.
+//
+// Operator-precedence section is parsed by the pratt parser,
+// levels are listed from low to high precedence.
+//
+//	%operators Expr <- Number
+//	    %left    '+' @add '-' @sub
+//	    %left    '*' @mul
+//	    %right   '^' @pow
+//	    %prefix  '-' @neg
+//	    %postfix '!' @factorial
+//
+// The operator actions are called with the operands and operator in the order of appearance.
+// The first rule is the start rule, unless specified by %start directive.
+// Every rule is memoized, so direct left recursion is supported.
+package peg
+
+import (
+	"io/ioutil"
+	"sort"
+
+	"github.com/modern-go/parse"
+)
+
+// Action is called with the values of the sequence, returns the value of the sequence
+type Action func(values []interface{}) interface{}
+
+// Actions is the callbacks can be referenced in grammar by name
+type Actions map[string]Action
+
+// Parser is compiled from grammar
+type Parser struct {
+	start string
+	rules map[string]*parse.Rule
+}
+
+// Compile compile the grammar text into parser.
+// The errors found in grammar, like undefined rules and unreachable alternatives,
+// are returned as ErrorList.
+func Compile(grammarText string, actions Actions) (*Parser, error) {
+	grammar, errs := parseGrammar(grammarText)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	scanner := &scanner{text: grammarText}
+	checkGrammar(scanner, grammar, actions)
+	if len(scanner.errors) > 0 {
+		errs := scanner.errors
+		sort.SliceStable(errs, func(i, j int) bool {
+			if errs[i].Line != errs[j].Line {
+				return errs[i].Line < errs[j].Line
+			}
+			return errs[i].Column < errs[j].Column
+		})
+		return nil, errs
+	}
+	c := &compiler{actions: actions, rules: map[string]*parse.Rule{}}
+	for _, rule := range grammar.rules {
+		c.rules[rule.name] = new(parse.Rule)
+	}
+	for _, rule := range grammar.rules {
+		*c.rules[rule.name] = parse.Memoize(c.compile(rule.expr))
+	}
+	return &Parser{start: grammar.start, rules: c.rules}, nil
+}
+
+// CompileFile compile the grammar file into parser
+func CompileFile(filename string, actions Actions) (*Parser, error) {
+	grammarText, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(string(grammarText), actions)
+}
+
+// Rule returns the rule by name, nil if not defined
+func (parser *Parser) Rule(name string) parse.Rule {
+	rule := parser.rules[name]
+	if rule == nil {
+		return nil
+	}
+	return *rule
+}
+
+// Parse parse the source with the start rule
+func (parser *Parser) Parse(src *parse.Source) interface{} {
+	return parser.Rule(parser.start)(src)
+}
+
+// String parse the whole string with the start rule.
+// If failed, the error is *parse.SyntaxError listing the expected alternatives at the farthest position.
+func (parser *Parser) String(input string) (interface{}, error) {
+	src, err := parse.NewSourceString(input)
+	if err != nil {
+		return nil, err
+	}
+	parsed := parser.Parse(src)
+	if src.Error() != nil {
+		return nil, src.ExpectedError()
+	}
+	src.StoreSavepoint()
+	src.Peek1()
+	trailing := src.Error() == nil
+	src.RollbackToSavepoint()
+	if trailing {
+		return nil, src.ExpectedError()
+	}
+	return parsed, nil
+}
@@ -0,0 +1,156 @@
+package peg_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/peg"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func number(values []interface{}) interface{} {
+	digits := ""
+	for _, digit := range values[0].([]interface{}) {
+		digits += digit.(string)
+	}
+	value, _ := strconv.Atoi(digits)
+	return value
+}
+
+var calcActions = peg.Actions{
+	"number": number,
+	"sum": func(values []interface{}) interface{} {
+		sum := values[0].(int)
+		for _, tail := range values[1].([]interface{}) {
+			sum += tail.([]interface{})[1].(int)
+		}
+		return sum
+	},
+	"sub": func(values []interface{}) interface{} {
+		return values[0].(int) - values[2].(int)
+	},
+	"mul": func(values []interface{}) interface{} {
+		return values[0].(int) * values[2].(int)
+	},
+	"pow": func(values []interface{}) interface{} {
+		result := 1
+		for i := 0; i < values[2].(int); i++ {
+			result *= values[0].(int)
+		}
+		return result
+	},
+	"neg": func(values []interface{}) interface{} {
+		return -values[1].(int)
+	},
+	"group": func(values []interface{}) interface{} {
+		return values[1]
+	},
+}
+
+func TestCompile(t *testing.T) {
+	t.Run("peg rules", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			Sum    <- Number ('+' Number)* @sum
+			Number <- [0-9]+ @number
+		`, calcActions)[0].(*peg.Parser)
+		must.Equal(10, must.Call(parser.String, "1+2+3+4")[0])
+		_, err := parser.String("1+")
+		must.Equal(`1:3: expected [0-9]; found EOF`, err.Error())
+		_, err = parser.String("1+2]")
+		must.Equal(`1:4: expected one of [0-9], "+"; found "]"`, err.Error())
+	}))
+	t.Run("ebnf rules", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			Sum    = Number { '+' Number } @sum ;
+			Number ::= Digit { Digit } @digits ;
+			Digit  = '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' ;
+		`, peg.Actions{
+			"sum": calcActions["sum"],
+			"digits": func(values []interface{}) interface{} {
+				return number([]interface{}{append([]interface{}{values[0]}, values[1].([]interface{})...)})
+			},
+		})[0].(*peg.Parser)
+		must.Equal(33, must.Call(parser.String, "11+22")[0])
+	}))
+	t.Run("left recursion", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			Expr   <- Expr '-' Number @sub / Number
+			Number <- [0-9]+ @number
+		`, calcActions)[0].(*peg.Parser)
+		must.Equal(4, must.Call(parser.String, "9-2-3")[0])
+	}))
+	t.Run("lookahead", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			Keyword <- 'if' ![a-z]
+		`, nil)[0].(*peg.Parser)
+		must.Equal([]interface{}{"if", nil}, must.Call(parser.String, "if")[0])
+		_, err := parser.String("iffy")
+		must.NotNil(err)
+	}))
+	t.Run("start directive", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			%start Greeting
+			Name     <- [a-z]+
+			Greeting <- 'hi ' Name
+		`, nil)[0].(*peg.Parser)
+		must.Equal([]interface{}{"hi ", []interface{}{"b", "o", "b"}},
+			must.Call(parser.String, "hi bob")[0])
+	}))
+	t.Run("use rule with source", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			Number <- [0-9]+ @number
+		`, calcActions)[0].(*peg.Parser)
+		src, _ := parse.NewSourceString("42;")
+		must.Equal(42, parser.Rule("Number")(src))
+		must.Equal(byte(';'), src.Peek1())
+		must.Nil(parser.Rule("Undefined"))
+	}))
+}
+
+func TestCompile_Operators(t *testing.T) {
+	parser := must.Call(peg.Compile, `
+		%operators Expr <- Atom
+			%left   '+' @sum '-' @sub
+			%left   '*' @mul
+			%right  '^' @pow
+			%prefix '-' @neg
+		Atom   <- Number / '(' Expr ')' @group
+		Number <- [0-9]+ @number
+	`, peg.Actions{
+		"number": number,
+		"sum": func(values []interface{}) interface{} {
+			return values[0].(int) + values[2].(int)
+		},
+		"sub":   calcActions["sub"],
+		"mul":   calcActions["mul"],
+		"pow":   calcActions["pow"],
+		"neg":   calcActions["neg"],
+		"group": calcActions["group"],
+	})[0].(*peg.Parser)
+	t.Run("precedence", test.Case(func(ctx context.Context) {
+		must.Equal(7, must.Call(parser.String, "1+2*3")[0])
+		must.Equal(9, must.Call(parser.String, "(1+2)*3")[0])
+	}))
+	t.Run("left associative", test.Case(func(ctx context.Context) {
+		must.Equal(4, must.Call(parser.String, "9-2-3")[0])
+	}))
+	t.Run("right associative", test.Case(func(ctx context.Context) {
+		must.Equal(512, must.Call(parser.String, "2^3^2")[0])
+	}))
+	t.Run("prefix", test.Case(func(ctx context.Context) {
+		must.Equal(-6, must.Call(parser.String, "-2*3")[0])
+		must.Equal(1, must.Call(parser.String, "3+-2")[0])
+	}))
+	t.Run("default values", test.Case(func(ctx context.Context) {
+		parser := must.Call(peg.Compile, `
+			%operators Expr <- [a-z]
+				%left   '+'
+				%postfix '!'
+		`, nil)[0].(*peg.Parser)
+		must.Equal([]interface{}{"a", "+", []interface{}{"b", "!"}},
+			must.Call(parser.String, "a+b!")[0])
+	}))
+}
@@ -0,0 +1,251 @@
+package peg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokDefine // identifier followed by <- = or ::=
+	tokArrow
+	tokLiteral
+	tokClass
+	tokAction
+	tokDirective
+	tokPunct
+)
+
+type token struct {
+	kind   tokenKind
+	offset int
+	text   string // identifier, literal value, action/directive name or punctuation
+	class  *classNode
+}
+
+// scanner split the grammar text into tokens.
+// It is context sensitive: in EBNF rules, [ ] is optional instead of character class.
+type scanner struct {
+	text   string
+	offset int
+	ebnf   bool
+	errors ErrorList
+}
+
+func (s *scanner) errorf(offset int, format string, args ...interface{}) {
+	line, column := position(s.text, offset)
+	s.errors = append(s.errors, &Error{Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// position convert byte offset into 1 based line and column
+func position(text string, offset int) (int, int) {
+	line := 1 + strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	return line, offset - lineStart + 1
+}
+
+func (s *scanner) skipSpace(offset int) int {
+	for offset < len(s.text) {
+		switch s.text[offset] {
+		case ' ', '\t', '\r', '\n':
+			offset++
+		case '#':
+			for offset < len(s.text) && s.text[offset] != '\n' {
+				offset++
+			}
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func isIdentStart(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isIdentPart(b byte) bool {
+	return isIdentStart(b) || (b >= '0' && b <= '9')
+}
+
+func (s *scanner) scanIdent(offset int) int {
+	for offset < len(s.text) && isIdentPart(s.text[offset]) {
+		offset++
+	}
+	return offset
+}
+
+// arrowLen tells the length of definition operator at offset, 0 if not found
+func (s *scanner) arrowLen(offset int) int {
+	rest := s.text[offset:]
+	switch {
+	case strings.HasPrefix(rest, "<-"):
+		return 2
+	case strings.HasPrefix(rest, "::="):
+		return 3
+	case strings.HasPrefix(rest, "="):
+		return 1
+	}
+	return 0
+}
+
+func (s *scanner) next() token {
+	s.offset = s.skipSpace(s.offset)
+	start := s.offset
+	if start >= len(s.text) {
+		return token{kind: tokEOF, offset: start}
+	}
+	b := s.text[start]
+	switch {
+	case isIdentStart(b):
+		s.offset = s.scanIdent(start)
+		kind := tokIdent
+		if s.arrowLen(s.skipSpace(s.offset)) > 0 {
+			kind = tokDefine
+		}
+		return token{kind: kind, offset: start, text: s.text[start:s.offset]}
+	case b == '@' || b == '%':
+		s.offset = s.scanIdent(start + 1)
+		if s.offset == start+1 {
+			s.errorf(start, "missing name after %q", b)
+		}
+		kind := tokAction
+		if b == '%' {
+			kind = tokDirective
+		}
+		return token{kind: kind, offset: start, text: s.text[start+1 : s.offset]}
+	case b == '\'' || b == '"':
+		return token{kind: tokLiteral, offset: start, text: s.scanLiteral(b)}
+	case b == '[' && !s.ebnf:
+		return token{kind: tokClass, offset: start, class: s.scanClass()}
+	}
+	if n := s.arrowLen(start); n > 0 {
+		s.offset += n
+		return token{kind: tokArrow, offset: start, text: s.text[start:s.offset]}
+	}
+	switch b {
+	case '/', '|', '&', '!', '*', '+', '?', '(', ')', '[', ']', '{', '}', '.', ';':
+		s.offset++
+		return token{kind: tokPunct, offset: start, text: s.text[start:s.offset]}
+	}
+	s.errorf(start, "unexpected character %q", b)
+	s.offset++
+	return s.next()
+}
+
+// scanLiteral scan quoted literal, the offset is at the opening quote
+func (s *scanner) scanLiteral(quote byte) string {
+	start := s.offset
+	s.offset++
+	var buf []byte
+	for {
+		if s.offset >= len(s.text) || s.text[s.offset] == '\n' {
+			s.errorf(start, "literal not terminated")
+			return string(buf)
+		}
+		b := s.text[s.offset]
+		if b == quote {
+			s.offset++
+			return string(buf)
+		}
+		if b == '\\' {
+			r := s.scanEscape()
+			buf = append(buf, string(r)...)
+			continue
+		}
+		buf = append(buf, b)
+		s.offset++
+	}
+}
+
+// scanEscape scan escape sequence, the offset is at the backslash
+func (s *scanner) scanEscape() rune {
+	start := s.offset
+	s.offset++
+	if s.offset >= len(s.text) {
+		s.errorf(start, "escape sequence not terminated")
+		return utf8.RuneError
+	}
+	b := s.text[s.offset]
+	s.offset++
+	switch b {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case '0':
+		return 0
+	case 'x', 'u':
+		size := 2
+		if b == 'u' {
+			size = 4
+		}
+		if s.offset+size > len(s.text) {
+			s.errorf(start, "invalid escape sequence")
+			s.offset = len(s.text)
+			return utf8.RuneError
+		}
+		code, err := strconv.ParseUint(s.text[s.offset:s.offset+size], 16, 32)
+		if err != nil {
+			s.errorf(start, "invalid escape sequence")
+		}
+		s.offset += size
+		return rune(code)
+	}
+	if b >= utf8.RuneSelf {
+		// escaped non-ASCII character stands for itself
+		s.offset--
+		r, size := utf8.DecodeRuneInString(s.text[s.offset:])
+		s.offset += size
+		return r
+	}
+	return rune(b)
+}
+
+// scanClass scan character class like [a-z_], the offset is at the [
+func (s *scanner) scanClass() *classNode {
+	start := s.offset
+	s.offset++
+	class := &classNode{}
+	if s.offset < len(s.text) && s.text[s.offset] == '^' {
+		class.negated = true
+		s.offset++
+	}
+	for {
+		if s.offset >= len(s.text) || s.text[s.offset] == '\n' {
+			s.errorf(start, "character class not terminated")
+			return class
+		}
+		if s.text[s.offset] == ']' {
+			s.offset++
+			class.text = s.text[start:s.offset]
+			return class
+		}
+		low := s.scanClassRune()
+		high := low
+		if s.offset+1 < len(s.text) && s.text[s.offset] == '-' && s.text[s.offset+1] != ']' {
+			s.offset++
+			high = s.scanClassRune()
+			if high < low {
+				s.errorf(start, "invalid character range %q-%q", low, high)
+			}
+		}
+		class.ranges = append(class.ranges, runeRange{low: low, high: high})
+	}
+}
+
+func (s *scanner) scanClassRune() rune {
+	if s.text[s.offset] == '\\' {
+		return s.scanEscape()
+	}
+	r, size := utf8.DecodeRuneInString(s.text[s.offset:])
+	s.offset += size
+	return r
+}
@@ -0,0 +1,117 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Position is the location in the source.
+// Offset is 0 based, counted in bytes.
+// Line and Column are 1 based, column is counted in bytes by default, see WithColumnMode.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (pos Position) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// Offset returns how many bytes before the cursor
+func (src *Source) Offset() int {
+	return src.base.Offset + src.nextIdx
+}
+
+// Position returns the position of the cursor
+func (src *Source) Position() Position {
+	return src.positionAt(src.nextIdx)
+}
+
+// PositionOf convert the offset into position.
+// The offset should not be beyond the bytes already read,
+// nor before the bytes discarded.
+func (src *Source) PositionOf(offset int) Position {
+	return src.positionAt(offset - src.base.Offset)
+}
+
+// Rebase tells the source it starts at the position of a larger input,
+// like a chunk of file, so positions are reported as in the whole input.
+// It should be called before reading.
+func (src *Source) Rebase(pos Position) {
+	if src.frame != nil {
+		src.frame.based = true
+	}
+	src.base = pos
+}
+
+// positionAt convert the index of readBytes into position.
+// The line starts are scanned incrementally, only the line of idx is measured for column.
+func (src *Source) positionAt(idx int) Position {
+	src.resolveBase()
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > len(src.readBytes) {
+		idx = len(src.readBytes)
+	}
+	src.scanLines(idx)
+	line := sort.SearchInts(src.lineStarts, idx+1)
+	if line == 0 {
+		return Position{
+			Offset: src.base.Offset + idx,
+			Line:   src.base.Line,
+			Column: src.base.Column + src.columns.width(src.readBytes[:idx]),
+		}
+	}
+	lineStart := src.lineStarts[line-1]
+	return Position{
+		Offset: src.base.Offset + idx,
+		Line:   src.base.Line + line,
+		Column: 1 + src.columns.width(src.readBytes[lineStart:idx]),
+	}
+}
+
+// scanLines records the line starts in readBytes before idx
+func (src *Source) scanLines(idx int) {
+	for src.scannedIdx < idx {
+		i := bytes.IndexByte(src.readBytes[src.scannedIdx:idx], '\n')
+		if i < 0 {
+			src.scannedIdx = idx
+			return
+		}
+		src.scannedIdx += i + 1
+		src.lineStarts = append(src.lineStarts, src.scannedIdx)
+	}
+}
+
+// discardRead drop the bytes before the cursor to keep memory bounded.
+// It does nothing if there is savepoint, the bytes might be replayed.
+// The memo and expectations refer to the dropped bytes, they are cleared.
+func (src *Source) discardRead() {
+	if !src.savepointStack.Empty() || src.nextIdx == 0 {
+		return
+	}
+	src.base = src.positionAt(src.nextIdx)
+	// the line starts after the cursor are kept, shifted to the new readBytes
+	line := sort.SearchInts(src.lineStarts, src.nextIdx+1)
+	n := copy(src.lineStarts, src.lineStarts[line:])
+	src.lineStarts = src.lineStarts[:n]
+	for i := range src.lineStarts {
+		src.lineStarts[i] -= src.nextIdx
+	}
+	src.scannedIdx -= src.nextIdx
+	if src.reader == nil {
+		// the bytes are owned by caller
+		src.readBytes = src.readBytes[src.nextIdx:]
+	} else {
+		n := copy(src.readBytes, src.readBytes[src.nextIdx:])
+		src.readBytes = src.readBytes[:n]
+	}
+	src.nextIdx = 0
+	src.peekedIdx = 0
+	src.memo = nil
+	src.expectedOffset = -1
+	src.expected = src.expected[:0]
+}
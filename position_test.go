@@ -0,0 +1,42 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_Position(t *testing.T) {
+	t.Run("first line", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		src.ReadN(2)
+		must.Equal(2, src.Offset())
+		must.Equal(parse.Position{Offset: 2, Line: 1, Column: 3}, src.Position())
+	}))
+	t.Run("after newline", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "ab\ncd\nef")[0].(*parse.Source)
+		src.ReadN(7)
+		must.Equal("3:2", src.Position().String())
+		must.Equal("2:1", src.PositionOf(3).String())
+	}))
+	t.Run("rebase", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("ab\ncd"))[0].(*parse.Source)
+		src.Rebase(parse.Position{Offset: 10, Line: 3, Column: 5})
+		src.Read1()
+		must.Equal(parse.Position{Offset: 11, Line: 3, Column: 6}, src.Position())
+		src.ReadN(3)
+		must.Equal(parse.Position{Offset: 14, Line: 4, Column: 2}, src.Position())
+		must.Equal("3:7", src.PositionOf(12).String())
+	}))
+	t.Run("earlier position after later", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "a\nbc\n\nd\ne")[0].(*parse.Source)
+		src.ReadN(9)
+		must.Equal("5:2", src.Position().String())
+		must.Equal("2:2", src.PositionOf(3).String())
+		must.Equal("4:1", src.PositionOf(6).String())
+		must.Equal("1:1", src.PositionOf(0).String())
+	}))
+}
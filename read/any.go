@@ -1,68 +1,73 @@
 package read
 
 import (
+	"bytes"
+
 	"github.com/modern-go/parse"
 )
 
-// Until1 read any byte except b1.
-// If b1 not found, report error.
+// Until1 read any byte except b1, the bytes are owned.
+// If b1 not found, the bytes to EOF are read and EOF is reported, see UntilBytes to require b1.
 func Until1(src *parse.Source, b1 byte) []byte {
-	var buf []byte
-	for src.Error() == nil {
-		b := src.Peek1()
-		if b == b1 || src.Error() != nil {
-			break
-		}
-		src.Read1()
-		buf = append(buf, b)
-	}
-	return buf
+	return AppendUntil1(nil, src, b1)
+}
+
+// AppendUntil1 is Until1 appending to dst
+func AppendUntil1(dst []byte, src *parse.Source, b1 byte) []byte {
+	return appendUntil(dst, src, func(chunk []byte) int {
+		return bytes.IndexByte(chunk, b1)
+	})
 }
 
-// Until2 read any byte except b1 or b2.
-// If b1 not found, report error.
+// Until2 read any byte except b1 or b2, the bytes are owned.
+// If neither found, the bytes to EOF are read and EOF is reported, see UntilAny to require them.
 func Until2(src *parse.Source, b1 byte, b2 byte) []byte {
-	var buf []byte
-	for src.Error() == nil {
-		b := src.Peek1()
-		if b == b1 || b == b2 || src.Error() != nil {
-			break
-		}
-		src.Read1()
-		buf = append(buf, b)
-	}
-	return buf
+	return AppendUntil2(nil, src, b1, b2)
+}
+
+// AppendUntil2 is Until2 appending to dst
+func AppendUntil2(dst []byte, src *parse.Source, b1 byte, b2 byte) []byte {
+	var set parse.ByteSet
+	set.Add(b1).Add(b2)
+	return AppendUntilSet(dst, src, &set)
 }
 
-// AnyExcept1 read bytes until EOF, ignore b1
+// AnyExcept1 read bytes until EOF, ignore b1, the bytes are owned
 func AnyExcept1(src *parse.Source, b1 byte) []byte {
-	var buf []byte
-	b := src.Read1()
-	for src.Error() == nil {
-		if b != b1 {
-			buf = append(buf, b)
-		}
-		b = src.Read1()
-	}
-	return buf
+	return AppendAnyExcept1(nil, src, b1)
+}
+
+// AppendAnyExcept1 is AnyExcept1 appending to dst
+func AppendAnyExcept1(dst []byte, src *parse.Source, b1 byte) []byte {
+	var set parse.ByteSet
+	set.Add(b1)
+	return appendExcept(dst, src, &set)
 }
 
-// AnyExcepts read bytes until EOF, ignore bs
+// AnyExcepts read bytes until EOF, ignore bs, the bytes are owned
 func AnyExcepts(src *parse.Source, bs []byte) []byte {
-	var buf []byte
-	b := src.Read1()
+	return AppendAnyExcepts(nil, src, bs)
+}
+
+// AppendAnyExcepts is AnyExcepts appending to dst
+func AppendAnyExcepts(dst []byte, src *parse.Source, bs []byte) []byte {
+	var set parse.ByteSet
+	for _, b := range bs {
+		set.Add(b)
+	}
+	return appendExcept(dst, src, &set)
+}
+
+// appendExcept append the bytes not in set to buf, until EOF
+func appendExcept(buf []byte, src *parse.Source, set *parse.ByteSet) []byte {
 	for src.Error() == nil {
-		found := false
-		for _, b1 := range bs {
-			if b1 == b {
-				found = true
-				break
+		for _, b := range src.ReadN(len(src.Peek())) {
+			if !set.Contains(b) {
+				buf = append(buf, b)
 			}
 		}
-		if !found {
-			buf = append(buf, b)
-		}
-		b = src.Read1()
+		// fill more bytes, EOF is reported if no more
+		src.Peek1()
 	}
 	return buf
 }
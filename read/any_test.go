@@ -40,3 +40,34 @@ func TestExcept(t *testing.T) {
 		must.Equal("hellwrld1234", string(data))
 	}))
 }
+
+func TestAppend(t *testing.T) {
+	t.Run("append to dst", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("a,b;c")
+		dst := read.AppendUntil1([]byte("x="), src, ',')
+		src.Read1()
+		dst = read.AppendUntil2(dst, src, ';', ',')
+		must.Equal("x=ab", string(dst))
+	}))
+	t.Run("identifier", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("if x")
+		dst, keyword := read.AppendIdentifier([]byte("kw:"), src, &parse.IdentifierOptions{
+			Keywords: map[string]bool{"if": true}})
+		must.Equal("kw:if", string(dst))
+		must.Equal(true, keyword)
+	}))
+	t.Run("no allocation with enough capacity", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("hello world; next")
+		set := parse.NewByteRange('a', 'z')
+		buf := make([]byte, 0, 64)
+		allocs := testing.AllocsPerRun(100, func() {
+			src.StoreSavepoint()
+			buf = read.AppendUntil1(buf[:0], src, ';')
+			buf = read.AppendUntil2(buf[:0], src, ';', ',')
+			buf = read.AppendInSet(buf[:0], src, set)
+			buf = read.AppendAnyExcept1(buf[:0], src, ' ')
+			src.RollbackToSavepoint()
+		})
+		must.Equal(float64(0), allocs)
+	}))
+}
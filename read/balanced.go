@@ -0,0 +1,19 @@
+package read
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// Balanced read from the opening bracket to the matching closing one, the bytes are borrowed.
+// The nested brackets, quotes and comments are honored, nil options means all default.
+// If the brackets are not balanced, nothing is read and *parse.UnbalancedError is reported.
+func Balanced(src *parse.Source, opts *parse.BalancedOptions) []byte {
+	return borrow(src, func() int {
+		return src.SkipBalanced(opts)
+	})
+}
+
+// AppendBalanced is Balanced appending to dst
+func AppendBalanced(dst []byte, src *parse.Source, opts *parse.BalancedOptions) []byte {
+	return append(dst, Balanced(src, opts)...)
+}
@@ -0,0 +1,72 @@
+package read_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestBalanced(t *testing.T) {
+	code := &parse.BalancedOptions{
+		Brackets: []parse.Bracket{{Open: "{", Close: "}"}, {Open: "(", Close: ")"}},
+		Quotes:   []parse.Quote{{Open: `"`, Escape: '\\'}, {Open: "`"}},
+		Comments: []parse.Comment{{Start: "//"}, {Start: "/*", End: "*/"}},
+	}
+	testCases := []struct {
+		name    string
+		input   string
+		opts    *parse.BalancedOptions
+		matched string
+		err     string
+	}{
+		{name: "json value", input: `{"a": [1, {"b": "}"}], "c": '\''} tail`,
+			matched: `{"a": [1, {"b": "}"}], "c": '\''}`},
+		{name: "comments and quotes", input: "{ f(\"{\\\"\") // }\n /* } */ `}` } x", opts: code,
+			matched: "{ f(\"{\\\"\") // }\n /* } */ `}` }"},
+		{name: "multi-byte brackets", input: "{{ a {{ b }} }}c",
+			opts: &parse.BalancedOptions{Brackets: []parse.Bracket{{Open: "{{", Close: "}}"}}}, matched: "{{ a {{ b }} }}"},
+		{name: "nested comment", input: "( a (* ( *) (* (* *) ) *) )",
+			opts: &parse.BalancedOptions{
+				Brackets: []parse.Bracket{{Open: "(", Close: ")"}},
+				Comments: []parse.Comment{{Start: "(*", End: "*)", Nested: true}},
+			}, matched: "( a (* ( *) (* (* *) ) *) )"},
+		{name: "partial options", input: "[ // ]\n ']' ]x",
+			opts: &parse.BalancedOptions{Comments: parse.CComments}, matched: "[ // ]\n ']' ]"},
+		{name: "empty quotes", input: `("a)b`,
+			opts: &parse.BalancedOptions{Quotes: []parse.Quote{}}, matched: `("a)`},
+		{name: "not at bracket", input: "abc", err: `1:1: expected one of "(", "[", "{"; found "a"`},
+		{name: "mismatched", input: "(\n [1, 2)", err: `2:7: unexpected ")", expecting "]" to close "[" at 2:2`},
+		{name: "unclosed", input: "{(1)", err: `1:5: unexpected EOF, expecting "}" to close "{" at 1:1`},
+		{name: "unclosed quote", input: `["a]`, err: `1:5: unexpected EOF, expecting "\"" to close "\"" at 1:2`},
+		{name: "unclosed comment", input: "{ /* }", opts: code,
+			err: `1:7: unexpected EOF, expecting "*/" to close "/*" at 1:3`},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSource, strings.NewReader(testCase.input), 3)[0].(*parse.Source)
+			matched := read.Balanced(src, testCase.opts)
+			if testCase.err != "" {
+				must.Nil(matched)
+				must.Equal(testCase.err, src.Error().Error())
+				must.Equal(0, src.Offset())
+				return
+			}
+			must.Equal(testCase.matched, string(matched))
+			must.Nil(src.Error())
+			must.Equal(len(testCase.matched), src.Offset())
+		}))
+	}
+	t.Run("unbalanced error positions", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "(]")[0].(*parse.Source)
+		read.Balanced(src, nil)
+		err := src.Error().(*parse.UnbalancedError)
+		must.Equal(parse.Position{Offset: 0, Line: 1, Column: 1}, err.Opening)
+		must.Equal(parse.Position{Offset: 1, Line: 1, Column: 2}, err.Position)
+	}))
+}
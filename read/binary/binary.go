@@ -0,0 +1,171 @@
+// Package binary reads the fixed-width integers, floats, varints and length-prefixed bytes from the source.
+//
+// If the input is truncated, nothing is read and *TruncatedError is reported,
+// positioned at the start of the value. It unwraps to io.ErrUnexpectedEOF.
+package binary
+
+import (
+	stdbinary "encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/modern-go/parse"
+)
+
+// TruncatedError is reported if the input ends before the value is complete
+type TruncatedError struct {
+	parse.Position
+	// What is being read, like "uint32"
+	What string
+	Need int
+	Got  int
+}
+
+func (err *TruncatedError) Error() string {
+	return fmt.Sprintf("%v: unexpected EOF reading %s, need %d bytes, got %d", err.Position, err.What, err.Need, err.Got)
+}
+
+// Unwrap returns io.ErrUnexpectedEOF
+func (err *TruncatedError) Unwrap() error {
+	return io.ErrUnexpectedEOF
+}
+
+// OverflowError is reported if the varint or length does not fit
+type OverflowError struct {
+	parse.Position
+	Message string
+}
+
+func (err *OverflowError) Error() string {
+	return fmt.Sprintf("%v: %s", err.Position, err.Message)
+}
+
+// fixed read n bytes, nil if truncated
+func fixed(src *parse.Source, n int, what string) []byte {
+	if src.Error() != nil {
+		return nil
+	}
+	buf := src.PeekN(n)
+	if len(buf) < n {
+		if src.Error() == io.ErrUnexpectedEOF {
+			src.ReportError(&TruncatedError{Position: src.Position(), What: what, Need: n, Got: len(buf)})
+		}
+		return nil
+	}
+	return src.ReadN(n)
+}
+
+// Uint8 read one byte
+func Uint8(src *parse.Source) uint8 {
+	if buf := fixed(src, 1, "uint8"); buf != nil {
+		return buf[0]
+	}
+	return 0
+}
+
+// Int8 read one byte as signed
+func Int8(src *parse.Source) int8 {
+	if buf := fixed(src, 1, "int8"); buf != nil {
+		return int8(buf[0])
+	}
+	return 0
+}
+
+// Uint16BE read 2 bytes in big endian
+func Uint16BE(src *parse.Source) uint16 {
+	if buf := fixed(src, 2, "uint16"); buf != nil {
+		return stdbinary.BigEndian.Uint16(buf)
+	}
+	return 0
+}
+
+// Uint16LE read 2 bytes in little endian
+func Uint16LE(src *parse.Source) uint16 {
+	if buf := fixed(src, 2, "uint16"); buf != nil {
+		return stdbinary.LittleEndian.Uint16(buf)
+	}
+	return 0
+}
+
+// Uint32BE read 4 bytes in big endian
+func Uint32BE(src *parse.Source) uint32 {
+	if buf := fixed(src, 4, "uint32"); buf != nil {
+		return stdbinary.BigEndian.Uint32(buf)
+	}
+	return 0
+}
+
+// Uint32LE read 4 bytes in little endian
+func Uint32LE(src *parse.Source) uint32 {
+	if buf := fixed(src, 4, "uint32"); buf != nil {
+		return stdbinary.LittleEndian.Uint32(buf)
+	}
+	return 0
+}
+
+// Uint64BE read 8 bytes in big endian
+func Uint64BE(src *parse.Source) uint64 {
+	if buf := fixed(src, 8, "uint64"); buf != nil {
+		return stdbinary.BigEndian.Uint64(buf)
+	}
+	return 0
+}
+
+// Uint64LE read 8 bytes in little endian
+func Uint64LE(src *parse.Source) uint64 {
+	if buf := fixed(src, 8, "uint64"); buf != nil {
+		return stdbinary.LittleEndian.Uint64(buf)
+	}
+	return 0
+}
+
+// Int16BE read 2 bytes in big endian two's complement
+func Int16BE(src *parse.Source) int16 {
+	return int16(Uint16BE(src))
+}
+
+// Int16LE read 2 bytes in little endian two's complement
+func Int16LE(src *parse.Source) int16 {
+	return int16(Uint16LE(src))
+}
+
+// Int32BE read 4 bytes in big endian two's complement
+func Int32BE(src *parse.Source) int32 {
+	return int32(Uint32BE(src))
+}
+
+// Int32LE read 4 bytes in little endian two's complement
+func Int32LE(src *parse.Source) int32 {
+	return int32(Uint32LE(src))
+}
+
+// Int64BE read 8 bytes in big endian two's complement
+func Int64BE(src *parse.Source) int64 {
+	return int64(Uint64BE(src))
+}
+
+// Int64LE read 8 bytes in little endian two's complement
+func Int64LE(src *parse.Source) int64 {
+	return int64(Uint64LE(src))
+}
+
+// Float32BE read 4 bytes of IEEE 754 single precision in big endian
+func Float32BE(src *parse.Source) float32 {
+	return math.Float32frombits(Uint32BE(src))
+}
+
+// Float32LE read 4 bytes of IEEE 754 single precision in little endian
+func Float32LE(src *parse.Source) float32 {
+	return math.Float32frombits(Uint32LE(src))
+}
+
+// Float64BE read 8 bytes of IEEE 754 double precision in big endian
+func Float64BE(src *parse.Source) float64 {
+	return math.Float64frombits(Uint64BE(src))
+}
+
+// Float64LE read 8 bytes of IEEE 754 double precision in little endian
+func Float64LE(src *parse.Source) float64 {
+	return math.Float64frombits(Uint64LE(src))
+}
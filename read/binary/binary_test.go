@@ -0,0 +1,56 @@
+package binary_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read/binary"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestFixed(t *testing.T) {
+	t.Run("big and little endian", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{
+			0x12, 0x34, 0x34, 0x12,
+			0x12, 0x34, 0x56, 0x78, 0x78, 0x56, 0x34, 0x12,
+			1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1,
+		})[0].(*parse.Source)
+		must.Equal(uint16(0x1234), binary.Uint16BE(src))
+		must.Equal(uint16(0x1234), binary.Uint16LE(src))
+		must.Equal(uint32(0x12345678), binary.Uint32BE(src))
+		must.Equal(uint32(0x12345678), binary.Uint32LE(src))
+		must.Equal(uint64(0x0102030405060708), binary.Uint64BE(src))
+		must.Equal(uint64(0x0102030405060708), binary.Uint64LE(src))
+		must.Nil(src.Error())
+	}))
+	t.Run("signed", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{
+			0xFF, 0xFF, 0xFE, 0xFF, 0xFF, 0xFF, 0xFD, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFC,
+		})[0].(*parse.Source)
+		must.Equal(int8(-1), binary.Int8(src))
+		must.Equal(int16(-2), binary.Int16BE(src))
+		must.Equal(int32(-3), binary.Int32BE(src))
+		must.Equal(int64(-4), binary.Int64BE(src))
+	}))
+	t.Run("float", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{
+			0x3F, 0xC0, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF8, 0x7F,
+		})[0].(*parse.Source)
+		must.Equal(float32(1.5), binary.Float32BE(src))
+		must.Equal(true, math.IsNaN(binary.Float64LE(src)))
+	}))
+	t.Run("truncated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{0x01, 0x02, 0x03, 0x04, 0x05})[0].(*parse.Source)
+		must.Equal(uint32(0x01020304), binary.Uint32BE(src))
+		must.Equal(uint32(0), binary.Uint32LE(src))
+		err := src.Error().(*binary.TruncatedError)
+		must.Equal("1:5: unexpected EOF reading uint32, need 4 bytes, got 1", err.Error())
+		must.Equal(io.ErrUnexpectedEOF, err.Unwrap())
+		must.Equal(4, src.Offset())
+	}))
+}
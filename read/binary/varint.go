@@ -0,0 +1,109 @@
+package binary
+
+import (
+	stdbinary "encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/modern-go/parse"
+)
+
+// Uvarint read the unsigned LEB128 varint, as protobuf does.
+// *OverflowError is reported if it does not fit 64 bits.
+func Uvarint(src *parse.Source) uint64 {
+	return uvarint(src, "uvarint")
+}
+
+// Varint read the zigzag encoded signed varint, as protobuf sint64 does
+func Varint(src *parse.Source) int64 {
+	ux := uvarint(src, "varint")
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x
+}
+
+func uvarint(src *parse.Source, what string) uint64 {
+	if src.Error() != nil {
+		return 0
+	}
+	for n := 1; n <= stdbinary.MaxVarintLen64; n++ {
+		buf := src.PeekN(n)
+		if len(buf) < n {
+			if src.Error() == io.ErrUnexpectedEOF {
+				src.ReportError(&TruncatedError{Position: src.Position(), What: what, Need: n, Got: len(buf)})
+			}
+			return 0
+		}
+		if buf[n-1] < 0x80 {
+			x, size := stdbinary.Uvarint(buf)
+			if size <= 0 {
+				break
+			}
+			src.ReadN(n)
+			return x
+		}
+	}
+	src.ReportError(&OverflowError{Position: src.Position(), Message: what + " overflows 64 bits"})
+	return 0
+}
+
+// LengthPrefix reads the length before the bytes
+type LengthPrefix func(src *parse.Source) uint64
+
+// The popular length prefixes
+var (
+	PrefixUint8    LengthPrefix = func(src *parse.Source) uint64 { return uint64(Uint8(src)) }
+	PrefixUint16BE LengthPrefix = func(src *parse.Source) uint64 { return uint64(Uint16BE(src)) }
+	PrefixUint16LE LengthPrefix = func(src *parse.Source) uint64 { return uint64(Uint16LE(src)) }
+	PrefixUint32BE LengthPrefix = func(src *parse.Source) uint64 { return uint64(Uint32BE(src)) }
+	PrefixUint32LE LengthPrefix = func(src *parse.Source) uint64 { return uint64(Uint32LE(src)) }
+	PrefixUvarint  LengthPrefix = Uvarint
+)
+
+// Bytes read the length-prefixed bytes, the bytes are borrowed.
+// If truncated, nothing is read, including the prefix.
+func Bytes(src *parse.Source, prefix LengthPrefix) []byte {
+	if src.Error() != nil {
+		return nil
+	}
+	start := src.Offset()
+	src.StoreSavepoint()
+	length := prefix(src)
+	if src.Error() != nil {
+		return failed(src)
+	}
+	prefixLen := src.Offset() - start
+	if length > math.MaxInt32 {
+		src.RollbackToSavepoint()
+		src.ReportError(&OverflowError{Position: src.PositionOf(start), Message: fmt.Sprintf("length %d is too large", length)})
+		return nil
+	}
+	buf := src.PeekN(int(length))
+	if len(buf) < int(length) {
+		if src.Error() != io.ErrUnexpectedEOF {
+			return failed(src)
+		}
+		src.RollbackToSavepoint()
+		src.ReportError(&TruncatedError{Position: src.PositionOf(start), What: "length-prefixed bytes",
+			Need: prefixLen + int(length), Got: prefixLen + len(buf)})
+		return nil
+	}
+	src.DeleteSavepoint()
+	return src.ReadN(int(length))
+}
+
+// AppendBytes is Bytes appending to dst
+func AppendBytes(dst []byte, src *parse.Source, prefix LengthPrefix) []byte {
+	return append(dst, Bytes(src, prefix)...)
+}
+
+// failed rollback to the savepoint, keeping the error
+func failed(src *parse.Source) []byte {
+	err := src.Error()
+	src.RollbackToSavepoint()
+	src.ReportError(err)
+	return nil
+}
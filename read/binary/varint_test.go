@@ -0,0 +1,72 @@
+package binary_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read/binary"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestVarint(t *testing.T) {
+	t.Run("unsigned", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{0x01, 0xAC, 0x02, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01})[0].(*parse.Source)
+		must.Equal(uint64(1), binary.Uvarint(src))
+		must.Equal(uint64(300), binary.Uvarint(src))
+		must.Equal(uint64(1<<64-1), binary.Uvarint(src))
+		must.Nil(src.Error())
+	}))
+	t.Run("zigzag", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{0x00, 0x01, 0x02, 0x03})[0].(*parse.Source)
+		must.Equal(int64(0), binary.Varint(src))
+		must.Equal(int64(-1), binary.Varint(src))
+		must.Equal(int64(1), binary.Varint(src))
+		must.Equal(int64(-2), binary.Varint(src))
+	}))
+	t.Run("truncated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{0x01, 0xAC})[0].(*parse.Source)
+		binary.Uvarint(src)
+		must.Equal(uint64(0), binary.Uvarint(src))
+		must.Equal("1:2: unexpected EOF reading uvarint, need 2 bytes, got 1", src.Error().Error())
+		must.Equal(1, src.Offset())
+	}))
+	t.Run("overflow", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02})[0].(*parse.Source)
+		must.Equal(uint64(0), binary.Uvarint(src))
+		must.Equal("1:1: uvarint overflows 64 bits", src.Error().Error())
+	}))
+}
+
+func TestBytes(t *testing.T) {
+	t.Run("prefixes", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("\x02hi\x00\x03abc\x05hello"))[0].(*parse.Source)
+		must.Equal("hi", string(binary.Bytes(src, binary.PrefixUint8)))
+		must.Equal("abc", string(binary.Bytes(src, binary.PrefixUint16BE)))
+		must.Equal("<hello", string(binary.AppendBytes([]byte("<"), src, binary.PrefixUvarint)))
+		must.Nil(src.Error())
+	}))
+	t.Run("empty", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("\x00"))[0].(*parse.Source)
+		must.Equal("", string(binary.Bytes(src, binary.PrefixUint8)))
+		must.Nil(src.Error())
+	}))
+	t.Run("truncated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("\x00\x00\x00\x05hel"))[0].(*parse.Source)
+		must.Nil(binary.Bytes(src, binary.PrefixUint32BE))
+		must.Equal("1:1: unexpected EOF reading length-prefixed bytes, need 9 bytes, got 7", src.Error().Error())
+		must.Equal(0, src.Offset())
+	}))
+	t.Run("truncated prefix", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("\x00"))[0].(*parse.Source)
+		must.Nil(binary.Bytes(src, binary.PrefixUint16LE))
+		must.Equal("1:1: unexpected EOF reading uint16, need 2 bytes, got 1", src.Error().Error())
+		must.Equal(0, src.Offset())
+	}))
+	t.Run("too large", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("\xFF\xFF\xFF\xFF\x0F"))[0].(*parse.Source)
+		must.Nil(binary.Bytes(src, binary.PrefixUvarint))
+		must.Equal("1:1: length 4294967295 is too large", src.Error().Error())
+	}))
+}
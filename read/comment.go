@@ -0,0 +1,19 @@
+package read
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// Comments read the consecutive comments, like parse.CComments, the bytes are borrowed.
+// The line comment does not include the newline.
+// If the block comment is not terminated, nothing is read and *parse.UnterminatedError is reported.
+func Comments(src *parse.Source, syntax []parse.Comment) []byte {
+	return borrow(src, func() int {
+		return src.SkipComments(syntax)
+	})
+}
+
+// AppendComments is Comments appending to dst
+func AppendComments(dst []byte, src *parse.Source, syntax []parse.Comment) []byte {
+	return append(dst, Comments(src, syntax)...)
+}
@@ -0,0 +1,60 @@
+package read_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestComments(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		syntax  []parse.Comment
+		comment string
+		err     string
+	}{
+		{"c line", "// a\nx", parse.CComments, "// a", ""},
+		{"c block", "/* a */x", parse.CComments, "/* a */", ""},
+		{"consecutive blocks", "/* a *//* b */x", parse.CComments, "/* a *//* b */", ""},
+		{"shell", "# a\nx", parse.ShellComments, "# a", ""},
+		{"sql", "-- a\nx", parse.SQLComments, "-- a", ""},
+		{"lua block", "--[[ a\n b ]]x", parse.LuaComments, "--[[ a\n b ]]", ""},
+		{"lua line", "-- a\nx", parse.LuaComments, "-- a", ""},
+		{"ocaml nested", "(* a (* b *) c *)x", parse.OCamlComments, "(* a (* b *) c *)", ""},
+		{"html", "<!-- a -- b -->x", parse.HTMLComments, "<!-- a -- b -->", ""},
+		{"not comment", "x", parse.CComments, "", ""},
+		{"unterminated", "x\n  /* a", parse.CComments, "",
+			`2:3: unterminated comment, expecting "*/" to close "/*"`},
+		{"unterminated nested", "(* a (* b *)", parse.OCamlComments, "",
+			`1:1: unterminated comment, expecting "*)" to close "(*"`},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, testCase.input)[0].(*parse.Source)
+			if testCase.err != "" {
+				src.ReadN(bytesBefore(testCase.input))
+				must.Nil(read.Comments(src, testCase.syntax))
+				must.Equal(testCase.err, src.Error().Error())
+				return
+			}
+			must.Equal(testCase.comment, string(read.Comments(src, testCase.syntax)))
+			must.Equal(testCase.input[len(testCase.comment)], src.Peek1())
+		}))
+	}
+}
+
+// bytesBefore counts the bytes before the comment
+func bytesBefore(input string) int {
+	for i, c := range input {
+		if c == '/' || c == '(' {
+			return i
+		}
+	}
+	return 0
+}
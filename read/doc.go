@@ -0,0 +1,11 @@
+// Package read reads the bytes of syntax from the source, and returns them.
+//
+// The returned bytes are either owned or borrowed, as the doc of function tells.
+// The owned bytes are copied, the caller can keep and modify them.
+// The borrowed bytes are the buffer of source, like PeekN, they are valid until next read of the source.
+// They should not be modified, and should be copied if kept longer.
+//
+// Every reading function has the Append variant, like AppendUntil1 for Until1,
+// it appends the bytes to dst and returns the extended dst,
+// the buffer can be reused without allocation.
+package read
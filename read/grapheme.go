@@ -0,0 +1,29 @@
+package read
+
+import (
+	"unicode/utf8"
+
+	"github.com/modern-go/parse"
+)
+
+// Grapheme read one extended grapheme cluster of UAX #29,
+// like "é" or the emoji ZWJ sequence, what the user sees as one character.
+// The bytes are borrowed, the invalid UTF-8 is handled by the policy of source.
+func Grapheme(src *parse.Source) []byte {
+	cluster := src.PeekGrapheme()
+	if len(cluster) == 0 {
+		return nil
+	}
+	if r, n := src.PeekRune(); r == utf8.RuneError && n == 1 {
+		// the invalid byte might be returned as U+FFFD
+		src.ReadN(1)
+		return cluster
+	}
+	src.ReadN(len(cluster))
+	return cluster
+}
+
+// AppendGrapheme is Grapheme appending to dst
+func AppendGrapheme(dst []byte, src *parse.Source) []byte {
+	return append(dst, Grapheme(src)...)
+}
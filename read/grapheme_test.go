@@ -0,0 +1,57 @@
+package read_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestGrapheme(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		clusters []string
+	}{
+		{"ascii", "ab", []string{"a", "b"}},
+		{"crlf", "a\r\nb", []string{"a", "\r\n", "b"}},
+		{"combining marks", "e\u0323\u0301x", []string{"e\u0323\u0301", "x"}},
+		{"emoji zwj sequence", "\U0001F468\u200D\U0001F469\u200D\U0001F467!",
+			[]string{"\U0001F468\u200D\U0001F469\u200D\U0001F467", "!"}},
+		{"emoji modifier", "\U0001F44D\U0001F3FD.", []string{"\U0001F44D\U0001F3FD", "."}},
+		{"emoji presentation", "\u2764\uFE0F", []string{"\u2764\uFE0F"}},
+		{"flags", "\U0001F1EF\U0001F1F5\U0001F1FA\U0001F1F8\U0001F1EB",
+			[]string{"\U0001F1EF\U0001F1F5", "\U0001F1FA\U0001F1F8", "\U0001F1EB"}},
+		{"hangul jamo", "\u1100\u1161\u11A8\uAC00", []string{"\u1100\u1161\u11A8", "\uAC00"}},
+		{"spacing mark", "\u0915\u093F", []string{"\u0915\u093F"}},
+		{"prepend", "\u0600a", []string{"\u0600a"}},
+		{"control is alone", "\x00\u0301", []string{"\x00", "\u0301"}},
+		{"invalid byte", "\xffa", []string{"\xff", "a"}},
+		{"long cluster", "a" + strings.Repeat("\u0301", 20) + "b", []string{"a" + strings.Repeat("\u0301", 20), "b"}},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, testCase.input)[0].(*parse.Source)
+			var clusters []string
+			for src.Error() == nil {
+				cluster := read.Grapheme(src)
+				if cluster == nil {
+					break
+				}
+				clusters = append(clusters, string(cluster))
+			}
+			must.Equal(testCase.clusters, clusters)
+		}))
+	}
+	t.Run("invalid byte replaced", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\xffa",
+			parse.WithUTF8Policy(parse.UTF8Replace))[0].(*parse.Source)
+		must.Equal("\uFFFD", string(read.Grapheme(src)))
+		must.Equal("a", string(read.Grapheme(src)))
+	}))
+}
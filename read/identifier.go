@@ -0,0 +1,27 @@
+package read
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// Identifier read the identifier defined by options, nil options means UAX #31 default.
+// The bytes are borrowed, it reports whether the identifier is one of the keywords.
+// If not starting with identifier, "identifier" is reported as expected.
+func Identifier(src *parse.Source, opts *parse.IdentifierOptions) ([]byte, bool) {
+	keyword := false
+	ident := borrow(src, func() int {
+		var length int
+		length, keyword = src.SkipIdentifier(opts)
+		return length
+	})
+	if ident == nil {
+		return nil, false
+	}
+	return ident, keyword
+}
+
+// AppendIdentifier is Identifier appending to dst
+func AppendIdentifier(dst []byte, src *parse.Source, opts *parse.IdentifierOptions) ([]byte, bool) {
+	ident, keyword := Identifier(src, opts)
+	return append(dst, ident...), keyword
+}
@@ -0,0 +1,70 @@
+package read_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestIdentifier(t *testing.T) {
+	keywords := map[string]bool{"if": true, "für": true}
+	testCases := []struct {
+		name    string
+		input   string
+		opts    *parse.IdentifierOptions
+		ident   string
+		keyword bool
+		err     string
+	}{
+		{name: "ascii", input: "abc_1+", ident: "abc_1"},
+		{name: "underscore start", input: "_x y", ident: "_x"},
+		{name: "digit start", input: "1x", err: "identifier not found"},
+		{name: "chinese", input: "变量1=", ident: "变量1"},
+		{name: "greek", input: "αβγ.", ident: "αβγ"},
+		{name: "cyrillic", input: "переменная", ident: "переменная"},
+		{name: "combining mark continue", input: "e\u0301x", ident: "e\u0301x"},
+		{name: "combining mark start", input: "\u0301x", err: "identifier not found"},
+		{name: "devanagari", input: "नमस्ते!", ident: "नमस्ते"},
+		{name: "middle dot", input: "l·l", ident: "l·l"},
+		{name: "pattern syntax", input: "a⇒b", ident: "a"},
+		{name: "emoji", input: "😀", err: "identifier not found"},
+		{name: "nfkc modified", input: "\u309bx", err: "identifier not found"},
+		{name: "dollar", input: "$el.x", opts: &parse.IdentifierOptions{Dollar: true}, ident: "$el"},
+		{name: "dollar disabled", input: "$el", err: "identifier not found"},
+		{name: "hyphen", input: "font-size:", opts: &parse.IdentifierOptions{Hyphen: true}, ident: "font-size"},
+		{name: "hyphen start", input: "-x", opts: &parse.IdentifierOptions{Hyphen: true}, err: "identifier not found"},
+		{name: "nfc", input: "café", opts: &parse.IdentifierOptions{NFC: true}, ident: "café"},
+		{name: "not nfc", input: "cafe\u0301", opts: &parse.IdentifierOptions{NFC: true},
+			err: "identifier \"cafe\u0301\" is not in NFC"},
+		{name: "keyword", input: "if(", opts: &parse.IdentifierOptions{Keywords: keywords}, ident: "if", keyword: true},
+		{name: "non-ascii keyword", input: "für ", opts: &parse.IdentifierOptions{Keywords: keywords}, ident: "für", keyword: true},
+		{name: "keyword prefix", input: "iffy", opts: &parse.IdentifierOptions{Keywords: keywords}, ident: "iffy"},
+		{name: "mixed scripts", input: "p\u0430ypal", opts: &parse.IdentifierOptions{SafeScript: true},
+			err: "1:1: identifier \"p\u0430ypal\" mixes scripts Cyrillic, Latin"},
+		{name: "confusable with latin", input: "\u0430\u0440\u0435", opts: &parse.IdentifierOptions{SafeScript: true},
+			err: "1:1: identifier \"\u0430\u0440\u0435\" is confusable with Latin"},
+		{name: "single script", input: "переменная", opts: &parse.IdentifierOptions{SafeScript: true}, ident: "переменная"},
+		{name: "japanese with latin", input: "変数のx1", opts: &parse.IdentifierOptions{SafeScript: true}, ident: "変数のx1"},
+		{name: "greek with latin", input: "x\u03b1", opts: &parse.IdentifierOptions{SafeScript: true},
+			err: "1:1: identifier \"x\u03b1\" mixes scripts Greek, Latin"},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, testCase.input)[0].(*parse.Source)
+			ident, keyword := read.Identifier(src, testCase.opts)
+			if testCase.err != "" {
+				must.Equal(testCase.err, src.Error().Error())
+				must.Equal(0, src.Offset())
+				return
+			}
+			must.Equal(testCase.ident, string(ident))
+			must.Equal(testCase.keyword, keyword)
+			must.Equal(len(testCase.ident), src.Offset())
+		}))
+	}
+}
@@ -1,13 +1,15 @@
 package read
 
 import (
+	"io"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/modern-go/parse"
 )
 
-// UnicodeRange read unicode until one not in table,
-// the bytes will be appended to the space passed in.
+// UnicodeRange read unicode until one not in table, the bytes are borrowed.
+// The invalid UTF-8 is handled by the policy of source.
 func UnicodeRange(src *parse.Source, table *unicode.RangeTable) []byte {
 	src.StoreSavepoint()
 	length := 0
@@ -19,15 +21,16 @@ func UnicodeRange(src *parse.Source, table *unicode.RangeTable) []byte {
 		length += n
 		src.ReadN(n)
 	}
-	if src.FatalError() != nil {
-		src.DeleteSavepoint()
-		return nil
-	}
-	src.RollbackToSavepoint()
-	return src.PeekN(length)
+	return readSince(src, length)
 }
 
-// UnicodeRanges read unicode until one not in included table or encounteredd one in excluded table
+// AppendUnicodeRange is UnicodeRange appending to dst
+func AppendUnicodeRange(dst []byte, src *parse.Source, table *unicode.RangeTable) []byte {
+	return append(dst, UnicodeRange(src, table)...)
+}
+
+// UnicodeRanges read unicode until one not in included table or encounteredd one in excluded table.
+// The bytes are borrowed.
 func UnicodeRanges(src *parse.Source, includes []*unicode.RangeTable, excludes []*unicode.RangeTable) []byte {
 	src.StoreSavepoint()
 	length := 0
@@ -42,12 +45,36 @@ func UnicodeRanges(src *parse.Source, includes []*unicode.RangeTable, excludes [
 		length += n
 		src.ReadN(n)
 	}
-	if src.FatalError() != nil {
+	return readSince(src, length)
+}
+
+// AppendUnicodeRanges is UnicodeRanges appending to dst
+func AppendUnicodeRanges(dst []byte, src *parse.Source, includes []*unicode.RangeTable, excludes []*unicode.RangeTable) []byte {
+	return append(dst, UnicodeRanges(src, includes, excludes)...)
+}
+
+// readSince rollback to the savepoint, returns the bytes read since then, nil if failed
+func readSince(src *parse.Source, length int) []byte {
+	if err := src.Error(); err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		src.DeleteSavepoint()
 		return nil
 	}
 	src.RollbackToSavepoint()
-	return src.PeekN(length)
+	return replaceInvalid(src, src.PeekN(length))
+}
+
+// replaceInvalid replace the invalid bytes by U+FFFD in UTF8Replace policy
+func replaceInvalid(src *parse.Source, buf []byte) []byte {
+	if src.UTF8Policy() != parse.UTF8Replace || utf8.Valid(buf) {
+		return buf
+	}
+	replaced := make([]byte, 0, len(buf)+2)
+	for len(buf) > 0 {
+		r, n := utf8.DecodeRune(buf)
+		replaced = append(replaced, string(r)...)
+		buf = buf[n:]
+	}
+	return replaced
 }
 
 func matchRanges(ranges []*unicode.RangeTable, r rune) bool {
@@ -29,3 +29,24 @@ func TestUnicodeRanges(t *testing.T) {
 		must.Equal("ab中文c", string(id))
 	}))
 }
+
+func TestUnicodeRanges_UTF8Policy(t *testing.T) {
+	excludes := []*unicode.RangeTable{unicode.Pattern_Syntax}
+	t.Run("pass through", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("a\xffb,")
+		must.Equal("a\xffb", string(read.UnicodeRanges(src, nil, excludes)))
+	}))
+	t.Run("replace", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("a\xffb,", parse.WithUTF8Policy(parse.UTF8Replace))
+		must.Equal("a�b", string(read.UnicodeRanges(src, nil, excludes)))
+	}))
+	t.Run("strict", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("a\xffb,", parse.WithUTF8Policy(parse.UTF8Strict))
+		must.Nil(read.UnicodeRanges(src, nil, excludes))
+		must.Equal("1:2: invalid UTF-8 byte 0xff", src.Error().Error())
+	}))
+	t.Run("until EOF", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("中文")
+		must.Equal("中文", string(read.UnicodeRange(src, unicode.Han)))
+	}))
+}
@@ -0,0 +1,147 @@
+package read
+
+import (
+	"io"
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+
+	"github.com/modern-go/parse"
+)
+
+// AnchoredRegexp is the regexp only matching at the cursor, see CompileRegexp
+type AnchoredRegexp struct {
+	re      *regexp.Regexp
+	pattern string
+}
+
+// CompileRegexp compiles the pattern anchored at the start, like regexp.Compile does.
+// It should be compiled once, like package variable, then used by Regexp.
+func CompileRegexp(pattern string) (*AnchoredRegexp, error) {
+	return compileAnchored(pattern, syntax.Perl, false)
+}
+
+// CompileRegexpPOSIX is CompileRegexp with POSIX syntax and leftmost-longest semantics,
+// like regexp.CompilePOSIX does
+func CompileRegexpPOSIX(pattern string) (*AnchoredRegexp, error) {
+	return compileAnchored(pattern, syntax.POSIX, true)
+}
+
+// MustCompileRegexp is CompileRegexp panicking on error
+func MustCompileRegexp(pattern string) *AnchoredRegexp {
+	re, err := CompileRegexp(pattern)
+	if err != nil {
+		panic(`read: CompileRegexp(` + pattern + `): ` + err.Error())
+	}
+	return re
+}
+
+// MustCompileRegexpPOSIX is CompileRegexpPOSIX panicking on error
+func MustCompileRegexpPOSIX(pattern string) *AnchoredRegexp {
+	re, err := CompileRegexpPOSIX(pattern)
+	if err != nil {
+		panic(`read: CompileRegexpPOSIX(` + pattern + `): ` + err.Error())
+	}
+	return re
+}
+
+func compileAnchored(pattern string, flags syntax.Flags, longest bool) (*AnchoredRegexp, error) {
+	parsed, err := syntax.Parse(pattern, flags)
+	if err != nil {
+		return nil, err
+	}
+	// \A prepended to the parsed pattern, so the alternation is anchored as a whole
+	anchored := &syntax.Regexp{Op: syntax.OpConcat, Flags: parsed.Flags,
+		Sub: []*syntax.Regexp{{Op: syntax.OpBeginText}, parsed}}
+	re, err := regexp.Compile(anchored.String())
+	if err != nil {
+		return nil, err
+	}
+	if longest {
+		re.Longest()
+	}
+	return &AnchoredRegexp{re: re, pattern: pattern}, nil
+}
+
+// String returns the pattern compiled
+func (re *AnchoredRegexp) String() string {
+	return re.pattern
+}
+
+// NumSubexp returns the number of parenthesized subexpressions
+func (re *AnchoredRegexp) NumSubexp() int {
+	return re.re.NumSubexp()
+}
+
+// Regexp read the match of re at the cursor, returns the submatches, the bytes are borrowed.
+// The unmatched optional group is nil. The stream is matched rune by rune,
+// the fast path applies when the rest is buffered already, like source of string.
+// If not matched, nothing is read and the syntax error is reported.
+func Regexp(src *parse.Source, re *AnchoredRegexp) [][]byte {
+	loc := matchRegexp(src, re)
+	if loc == nil {
+		return nil
+	}
+	match := src.ReadN(loc[1])
+	if src.Error() != nil {
+		return nil
+	}
+	submatches := make([][]byte, len(loc)/2)
+	for i := range submatches {
+		if loc[2*i] >= 0 {
+			submatches[i] = match[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return submatches
+}
+
+// AppendRegexp is Regexp appending the whole match to dst, the submatches are not kept
+func AppendRegexp(dst []byte, src *parse.Source, re *AnchoredRegexp) []byte {
+	loc := matchRegexp(src, re)
+	if loc == nil {
+		return dst
+	}
+	return append(dst, src.ReadN(loc[1])...)
+}
+
+// matchRegexp returns the submatch index pairs, nil if not matched
+func matchRegexp(src *parse.Source, re *AnchoredRegexp) []int {
+	if src.Error() != nil {
+		return nil
+	}
+	var loc []int
+	if peekMore(src) {
+		loc = re.re.FindReaderSubmatchIndex(&runeReader{src: src})
+	} else {
+		loc = re.re.FindSubmatchIndex(src.Peek())
+	}
+	if src.Error() != nil {
+		return nil
+	}
+	if loc == nil {
+		src.ReportExpected("`" + re.String() + "`")
+		src.ReportError(src.ExpectedError())
+	}
+	return loc
+}
+
+// runeReader reads the runes after the cursor without moving it
+type runeReader struct {
+	src    *parse.Source
+	offset int
+	eof    bool
+}
+
+func (reader *runeReader) ReadRune() (rune, int, error) {
+	ahead := reader.src.Peek()
+	for !reader.eof && len(ahead)-reader.offset < utf8.UTFMax && !utf8.FullRune(ahead[reader.offset:]) {
+		reader.eof = !peekMore(reader.src)
+		ahead = reader.src.Peek()
+	}
+	if reader.offset >= len(ahead) {
+		return 0, 0, io.EOF
+	}
+	r, n := utf8.DecodeRune(ahead[reader.offset:])
+	reader.offset += n
+	return r, n, nil
+}
@@ -0,0 +1,101 @@
+package read_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	reader io.Reader
+	count  int
+}
+
+func (reader *countingReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	reader.count += n
+	return n, err
+}
+
+func TestRegexp(t *testing.T) {
+	version := read.MustCompileRegexp(`v(\d+)\.(\d+)(?:-([\pL\d]+))?`)
+	testCases := []struct {
+		name       string
+		input      string
+		submatches []string
+		rest       string
+	}{
+		{"all groups", "v1.22-rc1 x", []string{"v1.22-rc1", "1", "22", "rc1"}, " x"},
+		{"optional group", "v1.22 x", []string{"v1.22", "1", "22", ""}, " x"},
+		{"to EOF", "v1.2", []string{"v1.2", "1", "2", ""}, ""},
+		{"unicode", "v1.2-\u00e9t\u00e9!", []string{"v1.2-\u00e9t\u00e9", "1", "2", "\u00e9t\u00e9"}, "!"},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, test.Case(func(ctx context.Context) {
+			sources := map[string]*parse.Source{
+				"buffered": must.Call(parse.NewSourceString, testCase.input)[0].(*parse.Source),
+				"stream": must.Call(parse.NewSource,
+					iotest.OneByteReader(strings.NewReader(testCase.input)), 1)[0].(*parse.Source),
+			}
+			for _, src := range sources {
+				submatches := read.Regexp(src, version)
+				must.Equal(len(testCase.submatches), len(submatches))
+				for i, submatch := range submatches {
+					must.Equal(testCase.submatches[i], string(submatch))
+				}
+				must.Equal(testCase.rest, string(src.PeekAll()))
+			}
+		}))
+	}
+	t.Run("unmatched group is nil", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "v1.2")[0].(*parse.Source)
+		must.Nil(read.Regexp(src, version)[3])
+	}))
+	t.Run("anchored at cursor", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, iotest.OneByteReader(strings.NewReader("x v1.2")), 1)[0].(*parse.Source)
+		must.Nil(read.Regexp(src, version))
+		must.Equal("1:1: expected `v(\\d+)\\.(\\d+)(?:-([\\pL\\d]+))?`; found \"x\"", src.Error().Error())
+		src.ResetError()
+		must.Equal(0, src.Offset())
+	}))
+	t.Run("alternation anchored as a whole", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "xb")[0].(*parse.Source)
+		must.Nil(read.Regexp(src, read.MustCompileRegexp(`a|b`)))
+		must.Equal(0, src.Offset())
+	}))
+	t.Run("miss does not buffer the rest of stream", test.Case(func(ctx context.Context) {
+		reader := &countingReader{reader: strings.NewReader("x" + strings.Repeat("v1.2 ", 1000))}
+		src := must.Call(parse.NewSource, reader, 4)[0].(*parse.Source)
+		must.Nil(read.Regexp(src, version))
+		must.Equal(0, src.Offset())
+		must.Equal(true, reader.count <= 8)
+	}))
+	t.Run("leftmost longest", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		must.Equal("a", string(read.Regexp(src, read.MustCompileRegexp(`a|ab`))[0]))
+		src = must.Call(parse.NewSource, iotest.OneByteReader(strings.NewReader("abc")), 1)[0].(*parse.Source)
+		must.Equal("ab", string(read.Regexp(src, read.MustCompileRegexpPOSIX(`a|ab`))[0]))
+	}))
+	t.Run("bad pattern", test.Case(func(ctx context.Context) {
+		_, err := read.CompileRegexp(`(a`)
+		must.Equal("error parsing regexp: missing closing ): `(a`", err.Error())
+	}))
+	t.Run("empty match", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "x")[0].(*parse.Source)
+		must.Equal("", string(read.Regexp(src, read.MustCompileRegexp(`\d*`))[0]))
+		must.Equal(byte('x'), src.Peek1())
+	}))
+	t.Run("append", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "v1.2 x")[0].(*parse.Source)
+		must.Equal("tag=v1.2", string(read.AppendRegexp([]byte("tag="), src, version)))
+	}))
+}
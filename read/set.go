@@ -0,0 +1,46 @@
+package read
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// InSet read the bytes in set, the bytes are owned
+func InSet(src *parse.Source, set *parse.ByteSet) []byte {
+	return AppendInSet(nil, src, set)
+}
+
+// AppendInSet is InSet appending to dst
+func AppendInSet(dst []byte, src *parse.Source, set *parse.ByteSet) []byte {
+	return appendUntil(dst, src, func(chunk []byte) int {
+		if n := set.Span(chunk); n < len(chunk) {
+			return n
+		}
+		return -1
+	})
+}
+
+// UntilSet read the bytes not in set, the bytes are owned.
+// EOF is reported if none of the set found.
+func UntilSet(src *parse.Source, set *parse.ByteSet) []byte {
+	return AppendUntilSet(nil, src, set)
+}
+
+// AppendUntilSet is UntilSet appending to dst
+func AppendUntilSet(dst []byte, src *parse.Source, set *parse.ByteSet) []byte {
+	return appendUntil(dst, src, set.Index)
+}
+
+// appendUntil append the bytes before the end to buf, the end returns -1 if not found in chunk.
+// It scans the buffered bytes chunk by chunk, instead of byte by byte.
+func appendUntil(buf []byte, src *parse.Source, end func(chunk []byte) int) []byte {
+	for src.Error() == nil {
+		chunk := src.Peek()
+		if i := end(chunk); i >= 0 {
+			return append(buf, src.ReadN(i)...)
+		}
+		buf = append(buf, src.ReadN(len(chunk))...)
+		// fill more bytes, EOF is reported if no more
+		src.Peek1()
+	}
+	return buf
+}
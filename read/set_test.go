@@ -0,0 +1,69 @@
+package read_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestInSet(t *testing.T) {
+	digits := parse.NewByteRange('0', '9')
+	t.Run("across chunks", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("12345a"), 2)[0].(*parse.Source)
+		must.Equal("12345", string(read.InSet(src, digits)))
+		must.Nil(src.Error())
+		must.Equal(byte('a'), src.Peek1())
+	}))
+	t.Run("until EOF", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("123"), 2)[0].(*parse.Source)
+		must.Equal("123", string(read.InSet(src, digits)))
+		must.NotNil(src.Error())
+	}))
+}
+
+func TestUntilSet(t *testing.T) {
+	t.Run("found", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("key: value"), 2)[0].(*parse.Source)
+		must.Equal("key", string(read.UntilSet(src, parse.NewByteSet(":="))))
+		must.Equal(byte(':'), src.Peek1())
+	}))
+	t.Run("not found", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("key"), 2)[0].(*parse.Source)
+		must.Equal("key", string(read.UntilSet(src, parse.NewByteSet(":="))))
+		must.NotNil(src.FatalError())
+	}))
+}
+
+var benchmarkInput = []byte(strings.Repeat("abcdefghijklmnopqrstuvwxyz", 1000) + ";")
+
+func BenchmarkUntil1(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		src, _ := parse.NewSource(bytes.NewReader(benchmarkInput), 40)
+		read.Until1(src, ';')
+	}
+}
+
+func BenchmarkUntilSet(b *testing.B) {
+	set := parse.NewByteSet(";,")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		src, _ := parse.NewSource(bytes.NewReader(benchmarkInput), 40)
+		read.UntilSet(src, set)
+	}
+}
+
+func BenchmarkInSet(b *testing.B) {
+	set := parse.NewByteRange('a', 'z')
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		src, _ := parse.NewSourceBytes(benchmarkInput)
+		read.InSet(src, set)
+	}
+}
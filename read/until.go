@@ -0,0 +1,160 @@
+package read
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/modern-go/parse"
+)
+
+// UntilOptions configure how the delimiter is handled
+type UntilOptions struct {
+	// Consume reads the delimiter too, the delimiter is not included in returned bytes
+	Consume bool
+	// Required reports syntax error if EOF reached before the delimiter
+	Required bool
+}
+
+// UntilBytes read the bytes until the delimiter, like "*/" or "\r\n\r\n", the bytes are owned.
+// Without delimiter found, the bytes to EOF are read and EOF is reported,
+// which is syntax error if the delimiter is required.
+func UntilBytes(src *parse.Source, delim []byte, opts *UntilOptions) []byte {
+	return AppendUntilBytes(nil, src, delim, opts)
+}
+
+// AppendUntilBytes is UntilBytes appending to dst
+func AppendUntilBytes(dst []byte, src *parse.Source, delim []byte, opts *UntilOptions) []byte {
+	delims := [][]byte{delim}
+	dst, _ = appendUntilDelim(dst, src, opts, delims, indexDelims(delims))
+	return dst
+}
+
+// UntilAny read the bytes until any of the delimiters, the bytes are owned.
+// It returns which delimiter found, -1 if not found.
+// The delimiter found first wins, the longer one wins if found at same position.
+func UntilAny(src *parse.Source, opts *UntilOptions, delims ...[]byte) ([]byte, int) {
+	return AppendUntilAny(nil, src, opts, delims...)
+}
+
+// AppendUntilAny is UntilAny appending to dst
+func AppendUntilAny(dst []byte, src *parse.Source, opts *UntilOptions, delims ...[]byte) ([]byte, int) {
+	return appendUntilDelim(dst, src, opts, delims, indexDelims(delims))
+}
+
+// UntilUnescaped read the bytes until the delimiter not escaped, like the string literal, the bytes are owned.
+// The escape byte escapes the next byte, the escape sequences are kept as is.
+func UntilUnescaped(src *parse.Source, delim []byte, escape byte, opts *UntilOptions) []byte {
+	return AppendUntilUnescaped(nil, src, delim, escape, opts)
+}
+
+// AppendUntilUnescaped is UntilUnescaped appending to dst
+func AppendUntilUnescaped(dst []byte, src *parse.Source, delim []byte, escape byte, opts *UntilOptions) []byte {
+	dst, _ = appendUntilDelim(dst, src, opts, [][]byte{delim}, func(chunk []byte, eof bool) (int, int, int) {
+		for i := 0; i < len(chunk); i++ {
+			if chunk[i] == escape {
+				if i+1 == len(chunk) && !eof {
+					// the escaped byte is not read yet
+					return -1, -1, i
+				}
+				i++
+				continue
+			}
+			if bytes.HasPrefix(chunk[i:], delim) {
+				return i, 0, 0
+			}
+			if !eof && len(chunk)-i < len(delim) && bytes.HasPrefix(delim, chunk[i:]) {
+				return -1, -1, i
+			}
+		}
+		return -1, -1, len(chunk)
+	})
+	return dst
+}
+
+// delimFinder finds the delimiter in chunk, returns the start and which delimiter found.
+// If not found, start is -1 and it tells how many bytes can be read safely,
+// the rest might be part of delimiter or escape sequence straddling the chunks.
+type delimFinder func(chunk []byte, eof bool) (start int, which int, safe int)
+
+func indexDelims(delims [][]byte) delimFinder {
+	longest := 0
+	for _, delim := range delims {
+		if len(delim) > longest {
+			longest = len(delim)
+		}
+	}
+	return func(chunk []byte, eof bool) (int, int, int) {
+		start, which := -1, -1
+		for i, delim := range delims {
+			j := bytes.Index(chunk, delim)
+			if j >= 0 && (start == -1 || j < start || j == start && len(delim) > len(delims[which])) {
+				start, which = j, i
+			}
+		}
+		if start >= 0 && (eof || start+longest <= len(chunk)) {
+			return start, which, 0
+		}
+		safe := len(chunk) - longest + 1
+		if eof {
+			safe = len(chunk)
+		}
+		if safe < 0 {
+			safe = 0
+		}
+		if start >= 0 && safe > start {
+			// a longer delimiter might start here
+			safe = start
+		}
+		return -1, -1, safe
+	}
+}
+
+// appendUntilDelim append the bytes before the delimiter to buf, returns which delimiter found
+func appendUntilDelim(buf []byte, src *parse.Source, opts *UntilOptions, delims [][]byte, find delimFinder) ([]byte, int) {
+	if opts == nil {
+		opts = &UntilOptions{}
+	}
+	if src.Error() != nil {
+		return buf, -1
+	}
+	eof := false
+	for {
+		chunk := src.Peek()
+		start, which, safe := find(chunk, eof)
+		if start >= 0 {
+			buf = append(buf, src.ReadN(start)...)
+			if opts.Consume {
+				src.ReadN(len(delims[which]))
+			}
+			return buf, which
+		}
+		buf = append(buf, src.ReadN(safe)...)
+		if eof || src.Error() != nil {
+			break
+		}
+		eof = !peekMore(src)
+	}
+	if src.Error() == nil {
+		// EOF is reported
+		src.Peek1()
+	}
+	if opts.Required {
+		expected := make([]string, len(delims))
+		for i, delim := range delims {
+			expected[i] = strconv.Quote(string(delim))
+		}
+		src.ReportExpected(expected...)
+		src.ReportError(src.ExpectedError())
+	}
+	return buf, -1
+}
+
+// peekMore read more bytes into the buffer, it tells false at EOF without leaving source in error condition
+func peekMore(src *parse.Source) bool {
+	n := len(src.Peek()) + 1
+	src.StoreSavepoint()
+	src.PeekN(n)
+	more := src.Error() == nil
+	src.RollbackToSavepoint()
+	return more
+}
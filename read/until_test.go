@@ -0,0 +1,77 @@
+package read_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestUntilBytes(t *testing.T) {
+	t.Run("delimiter straddling chunks", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("a * b */c"), 2)[0].(*parse.Source)
+		must.Equal("a * b ", string(read.UntilBytes(src, []byte("*/"), nil)))
+		must.Equal("*/", string(src.PeekN(2)))
+	}))
+	t.Run("consume delimiter", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("GET /\r\nHost: x\r\n\r\nbody"), 3)[0].(*parse.Source)
+		must.Equal("GET /\r\nHost: x", string(read.UntilBytes(src, []byte("\r\n\r\n"),
+			&read.UntilOptions{Consume: true})))
+		must.Equal("body", string(src.ReadAll()))
+	}))
+	t.Run("not found", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("<!-- abc --"), 2)[0].(*parse.Source)
+		must.Equal("<!-- abc --", string(read.UntilBytes(src, []byte("-->"), nil)))
+		must.NotNil(src.FatalError())
+	}))
+	t.Run("required", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "/* abc\n*")[0].(*parse.Source)
+		must.Equal("/* abc\n*", string(read.UntilBytes(src, []byte("*/"), &read.UntilOptions{Required: true})))
+		must.Equal(`2:2: expected "*/"; found EOF`, src.Error().Error())
+	}))
+}
+
+func TestUntilAny(t *testing.T) {
+	t.Run("first found wins", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "ab;c,d")[0].(*parse.Source)
+		text, which := read.UntilAny(src, nil, []byte(","), []byte(";"))
+		must.Equal("ab", string(text))
+		must.Equal(1, which)
+	}))
+	t.Run("longer wins at same position", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("line\r\nnext"), 5)[0].(*parse.Source)
+		text, which := read.UntilAny(src, &read.UntilOptions{Consume: true}, []byte("\r"), []byte("\r\n"))
+		must.Equal("line", string(text))
+		must.Equal(1, which)
+		must.Equal("next", string(src.ReadAll()))
+	}))
+	t.Run("not found", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "abc")[0].(*parse.Source)
+		text, which := read.UntilAny(src, &read.UntilOptions{Required: true}, []byte("-->"), []byte("]]>"))
+		must.Equal("abc", string(text))
+		must.Equal(-1, which)
+		must.Equal(`1:4: expected one of "-->", "]]>"; found EOF`, src.Error().Error())
+	}))
+}
+
+func TestUntilUnescaped(t *testing.T) {
+	t.Run("escaped delimiter", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader(`a\"b\\"c`), 2)[0].(*parse.Source)
+		must.Equal(`a\"b\\`, string(read.UntilUnescaped(src, []byte(`"`), '\\',
+			&read.UntilOptions{Consume: true})))
+		must.Equal("c", string(src.ReadAll()))
+	}))
+	t.Run("escape at chunk end", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader(`ab\"""" x`), 3)[0].(*parse.Source)
+		must.Equal(`ab\"`, string(read.UntilUnescaped(src, []byte(`"""`), '\\', nil)))
+	}))
+	t.Run("unterminated", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, `abc\"`)[0].(*parse.Source)
+		read.UntilUnescaped(src, []byte(`"`), '\\', &read.UntilOptions{Required: true})
+		must.Equal(`1:6: expected "\""; found EOF`, src.Error().Error())
+	}))
+}
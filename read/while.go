@@ -0,0 +1,56 @@
+package read
+
+import (
+	"github.com/modern-go/parse"
+)
+
+// While read the bytes matching the predicate, the bytes are borrowed.
+// If fewer than repeat.Min matched, nothing is read and syntax error is reported.
+func While(src *parse.Source, pred func(b byte) bool, repeat *parse.Repeat) []byte {
+	return borrow(src, func() int {
+		return src.SkipWhile(pred, repeat)
+	})
+}
+
+// AppendWhile is While appending to dst
+func AppendWhile(dst []byte, src *parse.Source, pred func(b byte) bool, repeat *parse.Repeat) []byte {
+	return append(dst, While(src, pred, repeat)...)
+}
+
+// WhileRune read the runes matching the predicate, the bytes are borrowed.
+// It returns how many runes matched.
+// If fewer than repeat.Min matched, nothing is read and syntax error is reported.
+// The invalid UTF-8 is handled by the policy of source.
+func WhileRune(src *parse.Source, pred func(r rune) bool, repeat *parse.Repeat) ([]byte, int) {
+	count := 0
+	buf := borrow(src, func() int {
+		var length int
+		length, count = src.SkipWhileRune(pred, repeat)
+		return length
+	})
+	if buf == nil {
+		return nil, 0
+	}
+	return replaceInvalid(src, buf), count
+}
+
+// AppendWhileRune is WhileRune appending to dst
+func AppendWhileRune(dst []byte, src *parse.Source, pred func(r rune) bool, repeat *parse.Repeat) ([]byte, int) {
+	buf, count := WhileRune(src, pred, repeat)
+	return append(dst, buf...), count
+}
+
+// borrow skips by the scan, then reads the skipped bytes again as borrowed
+func borrow(src *parse.Source, scan func() int) []byte {
+	if src.Error() != nil {
+		return nil
+	}
+	src.StoreSavepoint()
+	length := scan()
+	if src.Error() != nil {
+		src.DeleteSavepoint()
+		return nil
+	}
+	src.RollbackToSavepoint()
+	return src.ReadN(length)
+}
@@ -0,0 +1,70 @@
+package read_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/parse/read"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func isHex(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'f' || 'A' <= b && b <= 'F'
+}
+
+func TestWhile(t *testing.T) {
+	hex := &parse.Repeat{Min: 2, Max: 4, Label: "hex digit"}
+	t.Run("unbounded across chunks", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("12ab34g"), 2)[0].(*parse.Source)
+		must.Equal("12ab34", string(read.While(src, isHex, nil)))
+		must.Equal(byte('g'), src.Peek1())
+	}))
+	t.Run("max", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSource, strings.NewReader("12ab34"), 2)[0].(*parse.Source)
+		must.Equal("12ab", string(read.While(src, isHex, hex)))
+		must.Equal("34", string(src.ReadAll()))
+	}))
+	t.Run("min not met", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, `\x1g`)[0].(*parse.Source)
+		src.ReadN(2)
+		must.Nil(read.While(src, isHex, hex))
+		must.Equal(`1:4: expected hex digit; found "g"`, src.Error().Error())
+		must.Equal(2, src.Offset())
+	}))
+	t.Run("min not met at EOF", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "1")[0].(*parse.Source)
+		must.Nil(read.While(src, isHex, &parse.Repeat{Min: 2}))
+		must.Equal(`1:2: expected matching byte; found EOF`, src.Error().Error())
+	}))
+	t.Run("append", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "ff;")[0].(*parse.Source)
+		must.Equal("0xff", string(read.AppendWhile([]byte("0x"), src, isHex, hex)))
+	}))
+}
+
+func TestWhileRune(t *testing.T) {
+	t.Run("count runes", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "中文ab1")[0].(*parse.Source)
+		text, count := read.WhileRune(src, unicode.IsLetter, nil)
+		must.Equal("中文ab", string(text))
+		must.Equal(4, count)
+		must.Equal(byte('1'), src.Peek1())
+	}))
+	t.Run("bounds", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "中文字")[0].(*parse.Source)
+		text, count := read.WhileRune(src, unicode.IsLetter, &parse.Repeat{Max: 2})
+		must.Equal("中文", string(text))
+		must.Equal(2, count)
+	}))
+	t.Run("min not met", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "中1")[0].(*parse.Source)
+		text, count := read.WhileRune(src, unicode.IsLetter, &parse.Repeat{Min: 2, Label: "letter"})
+		must.Nil(text)
+		must.Equal(0, count)
+		must.Equal(`1:4: expected letter; found "1"`, src.Error().Error())
+	}))
+}
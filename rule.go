@@ -0,0 +1,81 @@
+package parse
+
+import (
+	"errors"
+)
+
+// Rule is a parsing sub-routine in combinator form.
+// Like the tokens, it reports failure by src.ReportError,
+// the cursor position after a failure does not matter, callers should rollback.
+type Rule func(src *Source) interface{}
+
+var errNoAlternative = errors.New("no alternative matched")
+
+// Literal match the bytes exactly, the matched string is returned
+func Literal(literal string) Rule {
+	expect := []byte(literal)
+	return func(src *Source) interface{} {
+		if src.Error() != nil {
+			return nil
+		}
+		if !src.Expect(expect) {
+			src.ReportError(errExpectedBytesNotFound)
+			return nil
+		}
+		return literal
+	}
+}
+
+// Sequence match the rules one after another, values are returned as []interface{}
+func Sequence(rules ...Rule) Rule {
+	return func(src *Source) interface{} {
+		values := make([]interface{}, 0, len(rules))
+		for _, rule := range rules {
+			value := rule(src)
+			if src.Error() != nil {
+				return nil
+			}
+			values = append(values, value)
+		}
+		return values
+	}
+}
+
+// Choice is the ordered choice, the first rule matched wins.
+// The cursor is rolled back before trying next rule.
+func Choice(rules ...Rule) Rule {
+	return func(src *Source) interface{} {
+		if src.Error() != nil {
+			return nil
+		}
+		for _, rule := range rules {
+			src.StoreSavepoint()
+			value := rule(src)
+			if src.Error() == nil {
+				src.DeleteSavepoint()
+				return value
+			}
+			src.RollbackToSavepoint()
+		}
+		src.ReportError(errNoAlternative)
+		return nil
+	}
+}
+
+// Action transform the value of the rule when matched
+func Action(rule Rule, action func(value interface{}) interface{}) Rule {
+	return func(src *Source) interface{} {
+		value := rule(src)
+		if src.Error() != nil {
+			return nil
+		}
+		return action(value)
+	}
+}
+
+// Ref reference a rule defined later, used to build recursive rules
+func Ref(rule *Rule) Rule {
+	return func(src *Source) interface{} {
+		return (*rule)(src)
+	}
+}
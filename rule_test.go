@@ -0,0 +1,72 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestLiteral(t *testing.T) {
+	t.Run("match", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("hello world")
+		must.Equal("hello", parse.Literal("hello")(src))
+		must.Nil(src.Error())
+		must.Equal(byte(' '), src.Peek1())
+	}))
+	t.Run("not match", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("hello world")
+		must.Nil(parse.Literal("world")(src))
+		must.NotNil(src.Error())
+	}))
+}
+
+func TestSequence(t *testing.T) {
+	t.Run("all matched", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("ab")
+		rule := parse.Sequence(parse.Literal("a"), parse.Literal("b"))
+		must.Equal([]interface{}{"a", "b"}, rule(src))
+		must.Nil(src.Error())
+	}))
+	t.Run("partially matched", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("ac")
+		rule := parse.Sequence(parse.Literal("a"), parse.Literal("b"))
+		must.Nil(rule(src))
+		must.NotNil(src.Error())
+	}))
+}
+
+func TestChoice(t *testing.T) {
+	t.Run("first matched wins", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("abc")
+		rule := parse.Choice(parse.Literal("ab"), parse.Literal("abc"))
+		must.Equal("ab", rule(src))
+		must.Equal(byte('c'), src.Peek1())
+	}))
+	t.Run("rollback before next", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("acd")
+		rule := parse.Choice(
+			parse.Sequence(parse.Literal("a"), parse.Literal("b")),
+			parse.Literal("ac"))
+		must.Equal("ac", rule(src))
+		must.Nil(src.Error())
+	}))
+	t.Run("none matched", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("xyz")
+		rule := parse.Choice(parse.Literal("a"), parse.Literal("b"))
+		must.Nil(rule(src))
+		must.NotNil(src.Error())
+	}))
+}
+
+func TestAction(t *testing.T) {
+	t.Run("transform value", test.Case(func(ctx context.Context) {
+		src, _ := parse.NewSourceString("ab")
+		rule := parse.Action(parse.Literal("ab"), func(value interface{}) interface{} {
+			return len(value.(string))
+		})
+		must.Equal(2, rule(src))
+	}))
+}
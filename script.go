@@ -0,0 +1,123 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// restrictiveScripts are the combinations of scripts allowed by
+// the highly restrictive profile of UTS #39, besides the single script
+var restrictiveScripts = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// latinConfusables are the Cyrillic and Greek letters looking like Latin letters
+var latinConfusables = map[rune]bool{}
+
+func init() {
+	for _, r := range "\u0430\u0412\u0432\u0415\u0435\u041A\u043A\u041C\u041D\u041E\u043E\u0420\u0440\u0421\u0441\u0422\u0443\u0425\u0445\u0406\u0456\u0408\u0458\u0405\u0455\u0501\u051B\u051D\u04BB\u04CF" +
+		"\u0391\u03B1\u0392\u0395\u0396\u0397\u0399\u03B9\u039A\u03BA\u039C\u039D\u03BD\u039F\u03BF\u03A1\u03C1\u03A4\u03C4\u03A5\u03C5\u03A7" {
+		latinConfusables[r] = true
+	}
+}
+
+// commonScripts are checked first, in order, before the rest of unicode.Scripts
+var commonScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Han", unicode.Han},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Arabic", unicode.Arabic},
+	{"Hangul", unicode.Hangul},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Devanagari", unicode.Devanagari},
+	{"Hebrew", unicode.Hebrew},
+	{"Thai", unicode.Thai},
+	{"Bopomofo", unicode.Bopomofo},
+}
+
+// otherScripts are the rest of unicode.Scripts, sorted by name
+var otherScripts []string
+
+func init() {
+	for name := range unicode.Scripts {
+		otherScripts = append(otherScripts, name)
+	}
+	sort.Strings(otherScripts)
+}
+
+// scriptOf returns the script of rune, empty for Common and Inherited
+func scriptOf(r rune) string {
+	if r < utf8.RuneSelf {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.Is(unicode.Common, r) || unicode.Is(unicode.Inherited, r) {
+		return ""
+	}
+	for _, script := range commonScripts {
+		if unicode.Is(script.table, r) {
+			return script.name
+		}
+	}
+	for _, name := range otherScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// checkScripts tells why the identifier is unsafe, empty if it is safe.
+// The identifier mixing scripts is rejected, unless the combination is highly restrictive.
+// The identifier of single script is rejected, if all letters look like Latin.
+func checkScripts(ident string) string {
+	scripts := map[string]bool{}
+	confusable := true
+	for _, r := range ident {
+		if script := scriptOf(r); script != "" {
+			scripts[script] = true
+		}
+		if unicode.IsLetter(r) && !latinConfusables[r] {
+			confusable = false
+		}
+	}
+	if len(scripts) > 1 && !isRestrictive(scripts) {
+		names := make([]string, 0, len(scripts))
+		for name := range scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Sprintf("mixes scripts %s", strings.Join(names, ", "))
+	}
+	if confusable && !scripts["Latin"] && (scripts["Cyrillic"] || scripts["Greek"]) {
+		return "is confusable with Latin"
+	}
+	return ""
+}
+
+func isRestrictive(scripts map[string]bool) bool {
+	for _, allowed := range restrictiveScripts {
+		count := 0
+		for _, name := range allowed {
+			if scripts[name] {
+				count++
+			}
+		}
+		if count == len(scripts) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
-	"unicode/utf8"
 )
 
 type stack struct {
@@ -13,7 +12,9 @@ type stack struct {
 }
 
 type breakInfo struct {
-	nextIdx int
+	nextIdx  int
+	lexers   int
+	literals int
 }
 
 func (s *stack) Push(info breakInfo) {
@@ -32,6 +33,8 @@ func (s *stack) Empty() bool {
 }
 
 // Source is generalization of io.Reader and []byte.
+// The bytes returned by Peek and Read methods are borrowed from the buffer,
+// they are valid until next read of the source, copy them if kept longer.
 // It supports read ahead.
 // It supports read byte by byte.
 // It supports read unicode code point by code point (as rune or []byte).
@@ -43,15 +46,39 @@ type Source struct {
 	buf            []byte
 	nextIdx        int
 	savepointStack *stack
+	memo           map[memoKey]*memoEntry
+	expectedOffset int
+	expected       []string
+	// base is the position of readBytes[0], moved forward when read bytes discarded
+	base Position
+	// lineStarts are the indexes of readBytes after newline, scanned up to scannedIdx
+	lineStarts []int
+	scannedIdx int
+	// peekedIdx is the farthest index examined (exclusive), beyond readBytes if EOF examined
+	peekedIdx int
+	tree      *treeRecorder
+	lexers    []Lexer
+	utf8      UTF8Policy
+	columns   ColumnMode
+	bidiCheck bool
+	// literals counts the nested literals entered, bidi control is allowed inside
+	literals int
+	// transcoder decodes the input in other encoding to UTF-8
+	transcoder *transcoder
+	// frame is set if the source is limited from the parent
+	frame *frame
 }
 
 const (
 	_maxBufLen = 40
 )
 
+// SourceOption configure the source when constructing
+type SourceOption func(src *Source)
+
 // NewSource construct a source from io.Reader.
 // At least one byte should be read from the io.Reader, otherwise error will be returned.
-func NewSource(reader io.Reader, bufLen int) (*Source, error) {
+func NewSource(reader io.Reader, bufLen int, opts ...SourceOption) (*Source, error) {
 	if bufLen > _maxBufLen {
 		bufLen = _maxBufLen
 	}
@@ -63,28 +90,52 @@ func NewSource(reader io.Reader, bufLen int) (*Source, error) {
 	readByes := make([]byte, n)
 
 	copy(readByes, buf)
-	return &Source{
+	return newSource(reader, readByes, buf, opts), nil
+}
+
+func newSource(reader io.Reader, readBytes []byte, buf []byte, opts []SourceOption) *Source {
+	src := &Source{
 		reader:         reader,
-		readBytes:      readByes,
+		readBytes:      readBytes,
 		buf:            buf,
 		savepointStack: new(stack),
-	}, nil
+		expectedOffset: -1,
+		base:           Position{Line: 1, Column: 1},
+	}
+	for _, opt := range opts {
+		opt(src)
+	}
+	if src.transcoder != nil {
+		// the bytes read so far are in original encoding
+		src.readBytes = nil
+		src.transcode(readBytes, reader == nil)
+	}
+	return src
 }
 
 // NewSourceString construct a source from string. Len should >= 1.
-func NewSourceString(str string) (*Source, error) {
+func NewSourceString(str string, opts ...SourceOption) (*Source, error) {
 	if len(str) == 0 {
 		return nil, errors.New("source string is empty")
 	}
 	reader := bytes.NewReader([]byte(str))
-	return NewSource(reader, _maxBufLen)
+	return NewSource(reader, _maxBufLen, opts...)
+}
+
+// NewSourceBytes construct a source from bytes. Len should >= 1.
+// The bytes are used directly without copying, should not be modified while parsing.
+func NewSourceBytes(data []byte, opts ...SourceOption) (*Source, error) {
+	if len(data) == 0 {
+		return nil, errors.New("source bytes is empty")
+	}
+	return newSource(nil, data, nil, opts), nil
 }
 
 // StoreSavepoint mark current position, and start recording.
 // Later we can rollback to current position.
 // Make sure there's no error, rollback will clear the error
 func (src *Source) StoreSavepoint() {
-	src.savepointStack.Push(breakInfo{nextIdx: src.nextIdx})
+	src.savepointStack.Push(breakInfo{nextIdx: src.nextIdx, lexers: len(src.lexers), literals: src.literals})
 }
 
 var errNoSavepoint = errors.New("no savepoint in stack")
@@ -107,7 +158,12 @@ func (src *Source) RollbackToSavepoint() {
 	}
 	brkInfo := src.savepointStack.Pop()
 	src.nextIdx = brkInfo.nextIdx
+	src.literals = brkInfo.literals
 	src.err = nil
+	// the lexers pushed after savepoint are dropped
+	for len(src.lexers) > brkInfo.lexers {
+		src.PopLexer()
+	}
 }
 
 // Peek1 return the first byte in the buffer to parse.
@@ -115,26 +171,43 @@ func (src *Source) Peek1() byte {
 	if src.Error() != nil {
 		return 0x0
 	}
-	if src.nextIdx < len(src.readBytes) {
-		return src.readBytes[src.nextIdx]
+	src.markPeeked(src.nextIdx + 1)
+	if src.nextIdx >= len(src.readBytes) {
+		src.consume()
 	}
-	src.consume()
-	if src.nextIdx < len(src.readBytes) {
-		return src.readBytes[src.nextIdx]
+	if src.nextIdx >= len(src.readBytes) {
+		// EOF
+		src.ReportError(io.ErrUnexpectedEOF)
+		return 0x0 //NULL
 	}
-
-	// EOF
-	src.ReportError(io.ErrUnexpectedEOF)
-	return 0x0 //NULL
+	b := src.readBytes[src.nextIdx]
+	if b == 0xE2 && src.bidiCheck && src.literals == 0 {
+		src.peekUpTo(3)
+		if src.checkBidi(src.nextIdx) {
+			return 0x0
+		}
+	}
+	return b
 }
 
 // Peek peeks as many bytes as possible without triggering consume
 func (src *Source) Peek() []byte {
+	src.markPeeked(len(src.readBytes))
 	return src.readBytes[src.nextIdx:]
 }
 
 // PeekAll peek all of the rest bytes
 func (src *Source) PeekAll() []byte {
+	// the EOF is examined
+	defer src.markPeeked(len(src.readBytes) + 1)
+	if src.transcoder != nil {
+		for src.fill() == nil {
+		}
+		return src.readBytes[src.nextIdx:]
+	}
+	if src.reader == nil {
+		return src.readBytes[src.nextIdx:]
+	}
 	data, _ := ioutil.ReadAll(src.reader)
 	if len(data) > 0 {
 		src.readBytes = append(src.readBytes, data...)
@@ -146,6 +219,7 @@ func (src *Source) PeekAll() []byte {
 // If N is longer than current buffer, it will read from reader.
 // The cursor will not be moved.
 func (src *Source) PeekN(n int) []byte {
+	src.markPeeked(src.nextIdx + n)
 	rest := len(src.readBytes) - src.nextIdx
 	for src.Error() == nil && rest < n {
 		src.consume()
@@ -182,6 +256,9 @@ func (src *Source) ReadN(n int) []byte {
 		return nil
 	}
 	buf := src.PeekN(n)
+	if src.checkBidiIn(buf) {
+		return nil
+	}
 	src.nextIdx += len(buf)
 	return buf
 }
@@ -196,114 +273,114 @@ func (src *Source) ReadAll() []byte {
 var errExpectedBytesNotFound = errors.New(`expected bytes not found`)
 
 // Expect1 like Read1
-// bytes will not be consumed if not match
+// bytes will not be consumed if not match,
+// the byte is recorded as expected for error reporting.
 func (src *Source) Expect1(b1 byte) bool {
-	if src.Peek1() == b1 {
+	if src.Error() != nil {
+		return false
+	}
+	if src.Peek1() == b1 && src.Error() == nil {
 		src.nextIdx++
 		return true
 	}
+	src.reportExpectedBytes([]byte{b1})
 	return false
 }
 
 // Expect2 like ReadN, with N == 2.
 // bytes will not be consumed if not match
 func (src *Source) Expect2(b1, b2 byte) bool {
-	buf := src.PeekN(2)
-	if len(buf) < 2 {
+	if src.Error() != nil {
 		return false
 	}
-	if buf[0] == b1 && buf[1] == b2 {
+	buf := src.PeekN(2)
+	if len(buf) == 2 && buf[0] == b1 && buf[1] == b2 {
 		src.nextIdx += 2
 		return true
 	}
+	src.reportExpectedBytes([]byte{b1, b2})
 	return false
 }
 
 // Expect3 like ReadN, with N == 3.
 // bytes will not be consumed if not match
 func (src *Source) Expect3(b1, b2, b3 byte) bool {
-	buf := src.PeekN(3)
-	if len(buf) < 3 {
+	if src.Error() != nil {
 		return false
 	}
-	if buf[0] == b1 && buf[1] == b2 && buf[2] == b3 {
+	buf := src.PeekN(3)
+	if len(buf) == 3 && buf[0] == b1 && buf[1] == b2 && buf[2] == b3 {
 		src.nextIdx += 3
 		return true
 	}
+	src.reportExpectedBytes([]byte{b1, b2, b3})
 	return false
 }
 
 // Expect4 like ReadN, with N == 4.
 // bytes will not be consumed if not match
 func (src *Source) Expect4(b1, b2, b3, b4 byte) bool {
-	buf := src.PeekN(4)
-	if len(buf) < 4 {
+	if src.Error() != nil {
 		return false
 	}
-	if buf[0] == b1 && buf[1] == b2 && buf[2] == b3 && buf[3] == b4 {
+	buf := src.PeekN(4)
+	if len(buf) == 4 && buf[0] == b1 && buf[1] == b2 && buf[2] == b3 && buf[3] == b4 {
 		src.nextIdx += 4
 		return true
 	}
+	src.reportExpectedBytes([]byte{b1, b2, b3, b4})
 	return false
 }
 
-// ExpectN like ReadN.
+// Expect like ReadN.
 // bytes will not be consumed if not match
 func (src *Source) Expect(expect []byte) bool {
-	buf := src.PeekN(len(expect))
-	if len(buf) < len(expect) {
+	if src.Error() != nil {
 		return false
 	}
-	for i, e := range expect {
-		if buf[i] != e {
-			return false
-		}
+	buf := src.PeekN(len(expect))
+	if bytes.Equal(buf, expect) {
+		src.nextIdx += len(expect)
+		return true
+	}
+	src.reportExpectedBytes(expect)
+	return false
+}
+
+// markPeeked track the farthest byte examined, the incremental reparsing needs it
+func (src *Source) markPeeked(end int) {
+	if end > src.peekedIdx {
+		src.peekedIdx = end
 	}
-	src.nextIdx += len(expect)
-	return true
 }
 
 // consume will fill the readBytes with more bytes from reader
 func (src *Source) consume() {
-	if src.reader == nil {
-		src.ReportError(io.EOF)
-		return
-	}
-	n, err := src.reader.Read(src.buf)
-	if err != nil || n == 0 {
+	if err := src.fill(); err != nil {
 		src.ReportError(err)
-		return
 	}
-	src.readBytes = append(src.readBytes, src.buf[:n]...)
 }
 
-// PeekRune read unicode code point as rune, without moving cursor.
-func (src *Source) PeekRune() (rune, int) {
-	p0 := src.Peek1()
-	x := first[p0]
-	if x >= as {
-		return utf8.DecodeRune(src.readBytes[src.nextIdx:])
+// fill read more bytes from reader into readBytes, without setting the error condition
+func (src *Source) fill() error {
+	if src.transcoder != nil {
+		return src.fillTranscoded()
 	}
-	sz := x & 7
-	fullBuf := src.PeekN(int(sz))
-	return utf8.DecodeRune(fullBuf)
-}
-
-// PeekUtf8 read one full code point without decoding into rune
-func (src *Source) PeekUtf8() []byte {
-	p0 := src.Peek1()
-	x := first[p0]
-	if x >= as {
-		return []byte{p0}
+	if src.reader == nil {
+		return io.EOF
+	}
+	n, err := src.reader.Read(src.buf)
+	if n > 0 {
+		src.readBytes = append(src.readBytes, src.buf[:n]...)
+		return nil
 	}
-	sz := int(x & 7)
-	fullBuf := src.PeekN(sz)
-	return fullBuf
+	return err
 }
 
 // ReportError set the source in error condition.
+// EOF can be replaced by more specific error.
 func (src *Source) ReportError(err error) {
-	if src.err == nil || src.err == io.EOF {
+	if src.err == nil || src.err == io.EOF || src.err == io.ErrUnexpectedEOF {
 		src.err = err
 	}
 }
@@ -4,7 +4,7 @@ set -e
 echo "" > coverage.txt
 
 for d in $(go list ./... | grep -v vendor); do
-    go test -coverprofile=profile.out -coverpkg=github.com/modern-go/parse,github.com/modern-go/parse/read,github.com/modern-go/parse/discard $d
+    go test -coverprofile=profile.out -coverpkg=github.com/modern-go/parse,github.com/modern-go/parse/read,github.com/modern-go/parse/discard,github.com/modern-go/parse/peg,github.com/modern-go/parse/parallel,github.com/modern-go/parse/layout $d
     if [ -f profile.out ]; then
         cat profile.out >> coverage.txt
         rm profile.out
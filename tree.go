@@ -0,0 +1,172 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+)
+
+// Tree is the parse result recording the span of every Parse call,
+// so that the input can be reparsed incrementally after edit.
+// The lexer should be stateless, the value of node only depends on the bytes examined.
+// The lexers are compared as map key, so they should be comparable, like pointer.
+type Tree struct {
+	Root  *Node
+	text  string
+	lexer Lexer
+}
+
+// Node is one call of Parse, the right hand side of infix is the child node
+type Node struct {
+	Start      int
+	End        int
+	Precedence int
+	Value      interface{}
+	Children   []*Node
+	lexer      Lexer
+	// lookahead is the farthest offset examined (exclusive), might be beyond End
+	lookahead int
+	// err is EOF if the end of input has been examined
+	err error
+}
+
+// Edit replaces Removed bytes at Offset with Inserted
+type Edit struct {
+	Offset   int
+	Removed  int
+	Inserted string
+}
+
+// NewTree parse the string with provided lexer, recording the nodes
+func NewTree(input string, lexer Lexer) (*Tree, error) {
+	return parseTree(input, lexer, &treeRecorder{})
+}
+
+// Value returns the value of root node
+func (tree *Tree) Value() interface{} {
+	return tree.Root.Value
+}
+
+// Text returns the input parsed
+func (tree *Tree) Text() string {
+	return tree.text
+}
+
+// Reparse apply the edit to the text, returns the new tree.
+// The nodes not examining the edited bytes are reused with span shifted,
+// only the nodes enclosing the edit are parsed again.
+// The actions of enclosing nodes are called again to build value from reused children.
+// The old tree is not modified, the edit out of range of text is error.
+func (tree *Tree) Reparse(edit Edit) (*Tree, error) {
+	if edit.Offset < 0 || edit.Removed < 0 || edit.Offset+edit.Removed > len(tree.text) {
+		return nil, fmt.Errorf("edit removing [%d,%d) is out of range of text length %d",
+			edit.Offset, edit.Offset+edit.Removed, len(tree.text))
+	}
+	text := tree.text[:edit.Offset] + edit.Inserted + tree.text[edit.Offset+edit.Removed:]
+	recorder := &treeRecorder{
+		reusable: map[treeKey]*Node{},
+		editEnd:  edit.Offset + edit.Removed,
+		delta:    len(edit.Inserted) - edit.Removed,
+	}
+	recorder.collect(tree.Root, edit.Offset)
+	return parseTree(text, tree.lexer, recorder)
+}
+
+func parseTree(input string, lexer Lexer, recorder *treeRecorder) (*Tree, error) {
+	src, err := NewSourceBytes([]byte(input))
+	if err != nil {
+		return nil, err
+	}
+	src.tree = recorder
+	recorder.parent = &Node{}
+	Parse(src, lexer, 0)
+	if err := src.parseError(); err != nil {
+		return nil, err
+	}
+	return &Tree{Root: recorder.parent.Children[0], text: input, lexer: lexer}, nil
+}
+
+type treeKey struct {
+	lexer      Lexer
+	start      int
+	precedence int
+}
+
+// treeRecorder builds the tree while parsing
+type treeRecorder struct {
+	parent *Node
+	// reusable is the old nodes not affected by the edit, keyed by the start in new text
+	reusable map[treeKey]*Node
+	editEnd  int
+	delta    int
+}
+
+// collect the old nodes can be reused, the nodes examined the edited bytes can not
+func (recorder *treeRecorder) collect(node *Node, editStart int) {
+	if node.lookahead <= editStart {
+		recorder.reusable[treeKey{node.lexer, node.Start, node.Precedence}] = node
+		return
+	}
+	if node.Start >= recorder.editEnd {
+		recorder.reusable[treeKey{node.lexer, node.Start + recorder.delta, node.Precedence}] = node
+		return
+	}
+	for _, child := range node.Children {
+		recorder.collect(child, editStart)
+	}
+}
+
+func (recorder *treeRecorder) parse(src *Source, lexer Lexer, precedence int) interface{} {
+	if src.Error() != nil {
+		return nil
+	}
+	start := src.nextIdx
+	if old := recorder.reusable[treeKey{lexer, start, precedence}]; old != nil {
+		node := old
+		if start != old.Start {
+			node = shiftNode(old, start-old.Start)
+		}
+		recorder.parent.Children = append(recorder.parent.Children, node)
+		src.nextIdx += node.End - node.Start
+		src.markPeeked(node.lookahead)
+		if node.err != nil {
+			src.ReportError(node.err)
+		}
+		return node.Value
+	}
+	node := &Node{Start: start, Precedence: precedence, lexer: lexer}
+	parent := recorder.parent
+	recorder.parent = node
+	peekedIdx := src.peekedIdx
+	src.peekedIdx = src.nextIdx
+	node.Value = parseLoop(src, lexer, precedence)
+	recorder.parent = parent
+	node.End = src.nextIdx
+	node.lookahead = src.peekedIdx
+	if node.lookahead < node.End {
+		node.lookahead = node.End
+	}
+	src.markPeeked(peekedIdx)
+	switch src.Error() {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		node.err = src.Error()
+	default:
+		// failed node is not recorded
+		return node.Value
+	}
+	parent.Children = append(parent.Children, node)
+	return node.Value
+}
+
+// shiftNode copy the node with span shifted
+func shiftNode(node *Node, delta int) *Node {
+	shifted := *node
+	shifted.Start += delta
+	shifted.End += delta
+	shifted.lookahead += delta
+	shifted.Children = make([]*Node, len(node.Children))
+	for i, child := range node.Children {
+		shifted.Children[i] = shiftNode(child, delta)
+	}
+	return &shifted
+}
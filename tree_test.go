@@ -0,0 +1,134 @@
+package parse_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+// arithLexer parse expression like 1 + 2 * 3
+type arithLexer struct {
+	// numbers counts how many numbers parsed
+	numbers int
+}
+
+func (lexer *arithLexer) skip(src *parse.Source) {
+	for src.Error() == nil && src.Peek1() == ' ' {
+		src.Read1()
+	}
+}
+
+func (lexer *arithLexer) PrefixToken(src *parse.Source) parse.PrefixToken {
+	lexer.skip(src)
+	b := src.Peek1()
+	if src.Error() == nil && b >= '0' && b <= '9' {
+		lexer.numbers++
+		return &numberToken{}
+	}
+	src.ReportExpected("number")
+	return nil
+}
+
+func (lexer *arithLexer) InfixToken(src *parse.Source) (parse.InfixToken, int) {
+	lexer.skip(src)
+	switch src.Peek1() {
+	case '+':
+		return &operatorToken{lexer: lexer}, 1
+	case '*':
+		return &operatorToken{lexer: lexer}, 2
+	}
+	src.ReportExpected(`"+"`, `"*"`)
+	return nil, 0
+}
+
+type operatorToken struct {
+	lexer *arithLexer
+}
+
+func (token *operatorToken) InfixParse(src *parse.Source, left interface{}) interface{} {
+	if src.Read1() == '*' {
+		right := parse.Parse(src, token.lexer, 2)
+		if right == nil {
+			return nil
+		}
+		return left.(int) * right.(int)
+	}
+	right := parse.Parse(src, token.lexer, 1)
+	if right == nil {
+		return nil
+	}
+	return left.(int) + right.(int)
+}
+
+// dumpNode describes the spans and values of the tree
+func dumpNode(node *parse.Node) string {
+	dump := fmt.Sprintf("[%d,%d)@%d=%v", node.Start, node.End, node.Precedence, node.Value)
+	for _, child := range node.Children {
+		dump += " " + dumpNode(child)
+	}
+	return "(" + dump + ")"
+}
+
+func TestTree_Reparse(t *testing.T) {
+	t.Run("same as full reparse", test.Case(func(ctx context.Context) {
+		edits := []parse.Edit{
+			{Offset: 10, Removed: 1, Inserted: "5"},
+			{Offset: 0, Removed: 0, Inserted: "9"},
+			{Offset: 3, Removed: 1, Inserted: "*7+"},
+			{Offset: 14, Removed: 0, Inserted: " * 2"},
+			{Offset: 4, Removed: 6, Inserted: ""},
+			{Offset: 0, Removed: 2, Inserted: "3 + 3"},
+		}
+		tree := must.Call(parse.NewTree, "11+22*33+44", &arithLexer{})[0].(*parse.Tree)
+		for _, edit := range edits {
+			tree = must.Call(tree.Reparse, edit)[0].(*parse.Tree)
+			full := must.Call(parse.NewTree, tree.Text(), &arithLexer{})[0].(*parse.Tree)
+			must.Equal(dumpNode(full.Root), dumpNode(tree.Root))
+		}
+		must.Equal("3 + 31*3+45 * 2", tree.Text())
+		must.Equal(186, tree.Value())
+	}))
+	t.Run("reuse nodes before edit", test.Case(func(ctx context.Context) {
+		lexer := &arithLexer{}
+		tree := must.Call(parse.NewTree, "11+22*33+44", lexer)[0].(*parse.Tree)
+		must.Equal(4, lexer.numbers)
+		lexer.numbers = 0
+		tree = must.Call(tree.Reparse, parse.Edit{Offset: 10, Removed: 1, Inserted: "5"})[0].(*parse.Tree)
+		// only 11 and 45 are parsed again
+		must.Equal(2, lexer.numbers)
+		must.Equal(11+22*33+45, tree.Value())
+	}))
+	t.Run("reuse nodes after edit with span shifted", test.Case(func(ctx context.Context) {
+		lexer := &arithLexer{}
+		tree := must.Call(parse.NewTree, "11+22*33+44", lexer)[0].(*parse.Tree)
+		lexer.numbers = 0
+		tree = must.Call(tree.Reparse, parse.Edit{Offset: 0, Removed: 1, Inserted: "99"})[0].(*parse.Tree)
+		must.Equal(1, lexer.numbers)
+		must.Equal(991+22*33+44, tree.Value())
+		must.Equal(4, tree.Root.Children[0].Start)
+		must.Equal(9, tree.Root.Children[0].End)
+	}))
+	t.Run("syntax error", test.Case(func(ctx context.Context) {
+		tree := must.Call(parse.NewTree, "1+2", &arithLexer{})[0].(*parse.Tree)
+		_, err := tree.Reparse(parse.Edit{Offset: 2, Removed: 1, Inserted: ""})
+		must.Equal(`1:3: expected number; found EOF`, err.Error())
+	}))
+	t.Run("edit out of range", test.Case(func(ctx context.Context) {
+		tree := must.Call(parse.NewTree, "1+2", &arithLexer{})[0].(*parse.Tree)
+		for _, edit := range []parse.Edit{
+			{Offset: -1, Removed: 1},
+			{Offset: 1, Removed: -1},
+			{Offset: 2, Removed: 2},
+			{Offset: 4},
+		} {
+			_, err := tree.Reparse(edit)
+			must.NotNil(err)
+		}
+		_, err := tree.Reparse(parse.Edit{Offset: 2, Removed: 2})
+		must.Equal("edit removing [2,4) is out of range of text length 3", err.Error())
+	}))
+}
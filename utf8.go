@@ -1,5 +1,105 @@
 package parse
 
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// UTF8Policy tells how PeekRune and PeekUtf8 handle the invalid UTF-8,
+// including overlong encoding, surrogate and truncated sequence.
+type UTF8Policy int
+
+const (
+	// UTF8PassThrough returns utf8.RuneError with size 1, the invalid byte is kept as is
+	UTF8PassThrough UTF8Policy = iota
+	// UTF8Replace returns utf8.RuneError with size 1, the invalid byte is read as U+FFFD
+	UTF8Replace
+	// UTF8Strict reports *InvalidUTF8Error at the position
+	UTF8Strict
+)
+
+// WithUTF8Policy set the policy for invalid UTF-8, default to UTF8PassThrough
+func WithUTF8Policy(policy UTF8Policy) SourceOption {
+	return func(src *Source) {
+		src.utf8 = policy
+	}
+}
+
+// UTF8Policy returns how the invalid UTF-8 is handled
+func (src *Source) UTF8Policy() UTF8Policy {
+	return src.utf8
+}
+
+// InvalidUTF8Error is reported in UTF8Strict policy
+type InvalidUTF8Error struct {
+	Position
+	Byte byte
+}
+
+func (err *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("%v: invalid UTF-8 byte %#x", err.Position, err.Byte)
+}
+
+const replacementChar = string(utf8.RuneError)
+
+// PeekRune read unicode code point as rune, without moving cursor.
+// The invalid byte is returned as utf8.RuneError with size 1,
+// in UTF8Strict policy, the error is reported and size is 0.
+func (src *Source) PeekRune() (rune, int) {
+	p0 := src.Peek1()
+	if src.Error() != nil {
+		return utf8.RuneError, 0
+	}
+	x := first[p0]
+	if x == as {
+		return rune(p0), 1
+	}
+	sz := 1
+	if x != xx {
+		sz = int(x & 7)
+	}
+	r, n := utf8.DecodeRune(src.peekUpTo(sz))
+	if r == utf8.RuneError && n == 1 && src.utf8 == UTF8Strict {
+		src.ReportError(&InvalidUTF8Error{Position: src.Position(), Byte: p0})
+		return utf8.RuneError, 0
+	}
+	return r, n
+}
+
+// PeekUtf8 read one full code point without decoding into rune.
+// The invalid byte is returned as is, or as U+FFFD in UTF8Replace policy,
+// in UTF8Strict policy, the error is reported and nil is returned.
+// The U+FFFD is a new slice, appending to the result does not overwrite the buffer.
+func (src *Source) PeekUtf8() []byte {
+	r, n := src.PeekRune()
+	if n == 0 {
+		return nil
+	}
+	if r == utf8.RuneError && n == 1 && src.utf8 == UTF8Replace {
+		return []byte(replacementChar)
+	}
+	return src.readBytes[src.nextIdx : src.nextIdx+n : src.nextIdx+n]
+}
+
+// peekUpTo peek at most n bytes, fewer bytes are returned at EOF without reporting error
+func (src *Source) peekUpTo(n int) []byte {
+	src.markPeeked(src.nextIdx + n)
+	for len(src.readBytes)-src.nextIdx < n {
+		if err := src.fill(); err != nil {
+			if err != io.EOF {
+				src.ReportError(err)
+			}
+			break
+		}
+	}
+	end := src.nextIdx + n
+	if end > len(src.readBytes) {
+		end = len(src.readBytes)
+	}
+	return src.readBytes[src.nextIdx:end]
+}
+
 const (
 	// These names of these constants are chosen to give nice alignment in the
 	// table below. The first nibble is an index into acceptRanges or F for
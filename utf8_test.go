@@ -0,0 +1,85 @@
+package parse_test
+
+import (
+	"context"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func TestSource_UTF8Policy(t *testing.T) {
+	invalids := []struct {
+		name  string
+		input string
+	}{
+		{"lone continuation", "\x80a"},
+		{"invalid first byte", "\xffa"},
+		{"overlong 2 bytes", "\xc0\x80"},
+		{"overlong 3 bytes", "\xe0\x80\xaf"},
+		{"overlong 4 bytes", "\xf0\x80\x80\xaf"},
+		{"surrogate", "\xed\xa0\x80"},
+		{"beyond max rune", "\xf4\x90\x80\x80"},
+		{"bad continuation", "\xe4\xb8a"},
+		{"truncated at EOF", "\xe4\xb8"},
+	}
+	for _, invalid := range invalids {
+		invalid := invalid
+		t.Run(invalid.name+" pass through", test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, invalid.input)[0].(*parse.Source)
+			r, n := src.PeekRune()
+			must.Equal(utf8.RuneError, r)
+			must.Equal(1, n)
+			must.Equal([]byte{invalid.input[0]}, src.PeekUtf8())
+			must.Nil(src.Error())
+		}))
+		t.Run(invalid.name+" replace", test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, invalid.input,
+				parse.WithUTF8Policy(parse.UTF8Replace))[0].(*parse.Source)
+			r, n := src.PeekRune()
+			must.Equal(utf8.RuneError, r)
+			must.Equal(1, n)
+			must.Equal([]byte("�"), src.PeekUtf8())
+			must.Nil(src.Error())
+		}))
+		t.Run(invalid.name+" strict", test.Case(func(ctx context.Context) {
+			src := must.Call(parse.NewSourceString, "ab\n"+invalid.input,
+				parse.WithUTF8Policy(parse.UTF8Strict))[0].(*parse.Source)
+			src.ReadN(3)
+			_, n := src.PeekRune()
+			must.Equal(0, n)
+			err := src.Error().(*parse.InvalidUTF8Error)
+			must.Equal(invalid.input[0], err.Byte)
+			must.Equal("2:1", err.Position.String())
+		}))
+	}
+	t.Run("replacement char is valid", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "�",
+			parse.WithUTF8Policy(parse.UTF8Strict))[0].(*parse.Source)
+		r, n := src.PeekRune()
+		must.Equal(utf8.RuneError, r)
+		must.Equal(3, n)
+		must.Nil(src.Error())
+	}))
+	t.Run("modifying the result is safe", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\x80\x80ab",
+			parse.WithUTF8Policy(parse.UTF8Replace))[0].(*parse.Source)
+		replaced := src.PeekUtf8()
+		replaced[0] = 'x'
+		src.Read1()
+		must.Equal([]byte("\uFFFD"), src.PeekUtf8())
+		src.Read1()
+		peeked := src.PeekUtf8()
+		must.Equal("aX", string(append(peeked, 'X')))
+		must.Equal("ab", string(src.PeekN(2)))
+	}))
+	t.Run("strict error message", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceBytes, []byte("a\xc0"),
+			parse.WithUTF8Policy(parse.UTF8Strict))[0].(*parse.Source)
+		src.Read1()
+		must.Nil(src.PeekUtf8())
+		must.Equal("1:2: invalid UTF-8 byte 0xc0", src.Error().Error())
+	}))
+}
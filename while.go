@@ -0,0 +1,100 @@
+package parse
+
+import "io"
+
+// Repeat bounds the repetition, nil means zero or more
+type Repeat struct {
+	// Min is the minimum units to match, syntax error is reported if not met
+	Min int
+	// Max is the maximum units to match, zero means unlimited
+	Max int
+	// Label describes the unit in syntax error, like "hex digit"
+	Label string
+}
+
+// bounds returns the min and max, max is -1 if unlimited
+func (repeat *Repeat) bounds() (int, int) {
+	if repeat == nil {
+		return 0, -1
+	}
+	if repeat.Max <= 0 {
+		return repeat.Min, -1
+	}
+	return repeat.Min, repeat.Max
+}
+
+// SkipWhile skip the bytes matching the predicate, returns how many bytes skipped.
+// If fewer than repeat.Min matched, nothing is skipped and syntax error is reported.
+func (src *Source) SkipWhile(pred func(b byte) bool, repeat *Repeat) int {
+	if src.Error() != nil {
+		return 0
+	}
+	_, max := repeat.bounds()
+	start := src.nextIdx
+	count := 0
+	for count != max {
+		chunk := src.Peek()
+		n := 0
+		for n < len(chunk) && count+n != max && pred(chunk[n]) {
+			n++
+		}
+		src.ReadN(n)
+		count += n
+		if n < len(chunk) || count == max {
+			break
+		}
+		// fill more bytes, EOF is reported if no more
+		if src.Peek1(); src.Error() != nil {
+			break
+		}
+	}
+	if !src.skipped(start, repeat, "matching byte", count) {
+		return 0
+	}
+	return count
+}
+
+// SkipWhileRune skip the runes matching the predicate,
+// returns how many bytes skipped and how many runes matched.
+// If fewer than repeat.Min matched, nothing is skipped and syntax error is reported.
+// The invalid UTF-8 is handled by the policy of source.
+func (src *Source) SkipWhileRune(pred func(r rune) bool, repeat *Repeat) (int, int) {
+	if src.Error() != nil {
+		return 0, 0
+	}
+	_, max := repeat.bounds()
+	start := src.nextIdx
+	count := 0
+	for count != max {
+		r, n := src.PeekRune()
+		if src.Error() != nil || !pred(r) {
+			break
+		}
+		src.ReadN(n)
+		count++
+	}
+	if !src.skipped(start, repeat, "matching rune", count) {
+		return 0, 0
+	}
+	return src.nextIdx - start, count
+}
+
+// skipped tells if the skipping from start succeeded.
+// If fewer than repeat.Min matched, the cursor goes back to start and syntax error is reported.
+// The EOF met while skipping is not an error, the bytes before it are skipped.
+func (src *Source) skipped(start int, repeat *Repeat, label string, count int) bool {
+	if err := src.Error(); err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false
+	}
+	if min, _ := repeat.bounds(); count < min {
+		if repeat != nil && repeat.Label != "" {
+			label = repeat.Label
+		}
+		src.ReportExpected(label)
+		src.nextIdx = start
+		src.ReportError(src.ExpectedError())
+		return false
+	}
+	src.err = nil
+	return true
+}
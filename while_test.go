@@ -0,0 +1,37 @@
+package parse_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/modern-go/parse"
+	"github.com/modern-go/test"
+	"github.com/modern-go/test/must"
+)
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+func TestSource_SkipWhile(t *testing.T) {
+	t.Run("eof is not kept", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "123")[0].(*parse.Source)
+		must.Equal(3, src.SkipWhile(isDigit, nil))
+		must.Nil(src.Error())
+		src.Peek1()
+		must.Equal(io.ErrUnexpectedEOF, src.Error())
+	}))
+	t.Run("minimum not met", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "1x")[0].(*parse.Source)
+		must.Equal(0, src.SkipWhile(isDigit, &parse.Repeat{Min: 2, Label: "digit"}))
+		must.Equal(`1:2: expected digit; found "x"`, src.Error().Error())
+		must.Equal(0, src.Offset())
+	}))
+	t.Run("runes", test.Case(func(ctx context.Context) {
+		src := must.Call(parse.NewSourceString, "\u00e9t\u00e9!")[0].(*parse.Source)
+		length, count := src.SkipWhileRune(func(r rune) bool { return r != '!' }, &parse.Repeat{Max: 2})
+		must.Equal(3, length)
+		must.Equal(2, count)
+	}))
+}